@@ -0,0 +1,51 @@
+package jwtee
+
+import (
+	"encoding/json"
+)
+
+// Audience represents "aud" claim, which is either a single string or an array of strings.
+// @see https://tools.ietf.org/html/rfc7519#section-4.1.3
+type Audience []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It supports string, array of strings and null input.
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var single string
+
+		err := json.Unmarshal(data, &single)
+		if err != nil {
+			return err
+		}
+
+		*a = Audience{single}
+
+		return nil
+	}
+
+	var multiple []string
+
+	err := json.Unmarshal(data, &multiple)
+	if err != nil {
+		return err
+	}
+
+	*a = multiple
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// Single audience is marshaled as a string.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+
+	return json.Marshal([]string(a))
+}
@@ -0,0 +1,101 @@
+package jwtee_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/constraint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudience_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		desc    string
+		claims  string
+		checker func(t *testing.T, claims jwtee.RegisteredClaims, err error)
+	}{
+		{
+			desc:   "successful unmarshalling of single audience",
+			claims: `{"aud":"my-api"}`,
+			checker: func(t *testing.T, claims jwtee.RegisteredClaims, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, jwtee.Audience{"my-api"}, claims.Aud)
+				assert.True(t, claims.IsPermittedFor("my-api"))
+				assert.NoError(t, constraint.NewPermittedFor("my-api").Validate(claims))
+			},
+		},
+		{
+			desc:   "successful unmarshalling of audience array",
+			claims: `{"aud":["my-api","other-api"]}`,
+			checker: func(t *testing.T, claims jwtee.RegisteredClaims, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, jwtee.Audience{"my-api", "other-api"}, claims.Aud)
+				assert.True(t, claims.IsPermittedFor("other-api"))
+				assert.False(t, claims.IsPermittedFor("unknown-api"))
+			},
+		},
+		{
+			desc:   "successful unmarshalling of null audience",
+			claims: `{"aud":null}`,
+			checker: func(t *testing.T, claims jwtee.RegisteredClaims, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, claims.Aud)
+			},
+		},
+		{
+			desc:   "failed on invalid audience type",
+			claims: `{"aud":42}`,
+			checker: func(t *testing.T, claims jwtee.RegisteredClaims, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			var claims jwtee.RegisteredClaims
+
+			err := json.Unmarshal([]byte(tt.claims), &claims)
+
+			tt.checker(t, claims, err)
+		})
+	}
+}
+
+func TestAudience_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		desc     string
+		aud      jwtee.Audience
+		expected string
+	}{
+		{
+			desc:     "single audience marshaled as string",
+			aud:      jwtee.Audience{"my-api"},
+			expected: `{"aud":"my-api"}`,
+		},
+		{
+			desc:     "multiple audiences marshaled as array",
+			aud:      jwtee.Audience{"my-api", "other-api"},
+			expected: `{"aud":["my-api","other-api"]}`,
+		},
+		{
+			desc:     "empty audience omitted",
+			aud:      nil,
+			expected: `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			data, err := json.Marshal(jwtee.RegisteredClaims{Aud: tt.aud})
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(data))
+		})
+	}
+}
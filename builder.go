@@ -3,9 +3,18 @@ package jwtee
 import (
 	"encoding"
 	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"sync"
+
 	"github.com/pkg/errors"
 )
 
+var (
+	// ErrRegisteredHeaderParam indicates that custom header parameter has the name of Header field.
+	ErrRegisteredHeaderParam = errors.New("header parameter is registered, use dedicated builder method")
+)
+
 // Builder used to build encoded and signed token.
 type Builder interface {
 	Build(claims encoding.BinaryMarshaler, signer Signer, key Key) (*DecodedParts, error)
@@ -14,6 +23,15 @@ type Builder interface {
 // TokenBuilder implements Builder.
 type TokenBuilder struct {
 	h Header
+
+	// params stores application specific header parameters.
+	params map[string]interface{}
+
+	// mu guards headers.
+	mu sync.RWMutex
+
+	// headers caches encoded header by algorithm.
+	headers map[Algorithm][]byte
 }
 
 // NewTokenBuilder returns new instance of TokenBuilder.
@@ -28,84 +46,298 @@ func NewTokenBuilder() *TokenBuilder {
 // WithKID used to setup the kid (key ID) Header Parameter.
 func (b *TokenBuilder) WithKID(kid string) *TokenBuilder {
 	b.h.Kid = kid
+	b.resetCache()
 
 	return b
 }
 
-// Build used to construct and encode JWT.
-func (b *TokenBuilder) Build(claims encoding.BinaryMarshaler, signer Signer, key Key) (*DecodedParts, error) {
-	// TODO: Possible to reduce allocation if encode parts in same buffer
-	encodedHeader := b.encodeHeader(signer)
+// WithType used to setup the typ (type) Header Parameter, "JWT" by default.
+// Empty typ removes the parameter.
+func (b *TokenBuilder) WithType(typ string) *TokenBuilder {
+	b.h.Typ = typ
+	b.resetCache()
 
-	rawClaims, encodedClaims, err := b.encodeClaims(claims)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to encode claims")
+	return b
+}
+
+// WithContentType used to setup the cty (content type) Header Parameter.
+func (b *TokenBuilder) WithContentType(cty string) *TokenBuilder {
+	b.h.Cty = cty
+	b.resetCache()
+
+	return b
+}
+
+// WithJKU used to setup the jku (JWK Set URL) Header Parameter.
+func (b *TokenBuilder) WithJKU(jku string) *TokenBuilder {
+	b.h.Jku = jku
+	b.resetCache()
+
+	return b
+}
+
+// WithJWK used to setup the jwk (JSON Web Key) Header Parameter.
+func (b *TokenBuilder) WithJWK(jwk json.RawMessage) *TokenBuilder {
+	b.h.Jwk = jwk
+	b.resetCache()
+
+	return b
+}
+
+// WithX5U used to setup the x5u (X.509 URL) Header Parameter.
+func (b *TokenBuilder) WithX5U(x5u string) *TokenBuilder {
+	b.h.X5u = x5u
+	b.resetCache()
+
+	return b
+}
+
+// WithX5T used to setup the x5t (X.509 certificate SHA-1 thumbprint) Header Parameter.
+func (b *TokenBuilder) WithX5T(x5t string) *TokenBuilder {
+	b.h.X5t = x5t
+	b.resetCache()
+
+	return b
+}
+
+// WithX5TS256 used to setup the x5t#S256 (X.509 certificate SHA-256 thumbprint) Header Parameter.
+func (b *TokenBuilder) WithX5TS256(x5t string) *TokenBuilder {
+	b.h.X5tS256 = x5t
+	b.resetCache()
+
+	return b
+}
+
+// WithX5C used to setup the x5c (X.509 certificate chain) Header Parameter.
+// Each certificate is base64 (not base64url) encoded DER.
+func (b *TokenBuilder) WithX5C(chain ...string) *TokenBuilder {
+	b.h.X5c = chain
+	b.resetCache()
+
+	return b
+}
+
+// WithCritical used to setup the crit (critical) Header Parameter.
+func (b *TokenBuilder) WithCritical(params ...string) *TokenBuilder {
+	b.h.Crit = params
+	b.resetCache()
+
+	return b
+}
+
+// WithHeaderParam used to setup application specific Header Parameter.
+// Registered parameters must be set with dedicated methods,
+// otherwise Build fails with ErrRegisteredHeaderParam.
+// The value is encoded once on the first Build.
+func (b *TokenBuilder) WithHeaderParam(name string, value interface{}) *TokenBuilder {
+	if b.params == nil {
+		b.params = make(map[string]interface{})
 	}
 
-	payload := b.buildPayload(encodedHeader, encodedClaims)
+	b.params[name] = value
+	b.resetCache()
+
+	return b
+}
 
-	signed, signature, err := b.signPayload(payload, signer, key)
+// Build used to construct and encode JWT.
+func (b *TokenBuilder) Build(claims encoding.BinaryMarshaler, signer Signer, key Key) (*DecodedParts, error) {
+	token, rawClaims, signature, err := b.appendBuild(nil, claims, signer, key)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to sign payload")
+		return nil, err
 	}
 
+	h := b.h
+	h.Alg = signer.GetAlgorithmID()
+
+	payloadLen := len(token) - 1 - base64.RawURLEncoding.EncodedLen(len(signature))
+
 	parts := &DecodedParts{
-		raw: signed,
-		header: Header{
-			Typ: "JWT",
-			Alg: signer.GetAlgorithmID(),
-		},
+		raw:       token,
+		header:    h,
 		claims:    rawClaims,
-		payload:   payload,
+		payload:   token[:payloadLen],
 		signature: signature,
 	}
 
 	return parts, nil
 }
 
-func (b *TokenBuilder) concatParts(p1, p2, sep []byte) []byte {
-	buf := make([]byte, len(p1)+len(p2)+1)
-	copy(buf[:len(p1)], p1)
-	copy(buf[len(p1):len(p1)+1], sep)
-	copy(buf[len(p1)+1:], p2)
+// AppendBuild appends compact serialization of JWT to dst and returns the extended buffer.
+// If signer implements SignatureSizer, dst grows at most once.
+// On failure dst returns unchanged.
+func (b *TokenBuilder) AppendBuild(dst []byte, claims encoding.BinaryMarshaler, signer Signer, key Key) ([]byte, error) {
+	token, _, _, err := b.appendBuild(dst, claims, signer, key)
+	if err != nil {
+		return dst, err
+	}
 
-	return buf
+	return token, nil
 }
 
-func (b *TokenBuilder) buildPayload(headers, claims []byte) []byte {
-	return b.concatParts(headers, claims, []byte{sep})
+func (b *TokenBuilder) appendBuild(
+	dst []byte, claims encoding.BinaryMarshaler, signer Signer, key Key,
+) (token, rawClaims, signature []byte, err error) {
+	encodedHeader, err := b.cachedHeader(signer.GetAlgorithmID())
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to encode header")
+	}
+
+	rawClaims, err = claims.MarshalBinary()
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to encode claims")
+	}
+
+	signatureSize := 0
+	if sizer, ok := signer.(SignatureSizer); ok {
+		signatureSize = sizer.SignatureSize(key)
+	}
+
+	start := len(dst)
+	token = grow(dst, len(encodedHeader)+1+
+		base64.RawURLEncoding.EncodedLen(len(rawClaims))+1+
+		base64.RawURLEncoding.EncodedLen(signatureSize))
+
+	token = append(token, encodedHeader...)
+	token = append(token, sep)
+	token = appendEncoded(token, rawClaims)
+
+	signature, err = signer.Sign(token[start:], key)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to sign payload")
+	}
+
+	token = append(token, sep)
+	token = appendEncoded(token, signature)
+
+	return token, rawClaims, signature, nil
 }
 
-func (b *TokenBuilder) signPayload(payload []byte, signer Signer, key Key) (signed, signature []byte, err error) {
-	signature, err = signer.Sign(payload, key)
+// cachedHeader returns encoded header for the algorithm, encoding it once per builder.
+func (b *TokenBuilder) cachedHeader(alg Algorithm) ([]byte, error) {
+	b.mu.RLock()
+	encoded, ok := b.headers[alg]
+	b.mu.RUnlock()
+
+	if ok {
+		return encoded, nil
+	}
+
+	encoded, err := b.encodeHeader(alg)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	encodedSignature := make([]byte, base64.RawURLEncoding.EncodedLen(len(signature)))
-	base64.RawURLEncoding.Encode(encodedSignature, signature)
+	b.mu.Lock()
+	if b.headers == nil {
+		b.headers = make(map[Algorithm][]byte)
+	}
+	b.headers[alg] = encoded
+	b.mu.Unlock()
 
-	signed = b.concatParts(payload, encodedSignature, []byte{sep})
+	return encoded, nil
+}
 
-	return signed, signature, nil
+// resetCache drops encoded headers after header change.
+func (b *TokenBuilder) resetCache() {
+	b.mu.Lock()
+	b.headers = nil
+	b.mu.Unlock()
 }
 
-func (b *TokenBuilder) encodeClaims(claims encoding.BinaryMarshaler) (raw, encoded []byte, err error) {
-	raw, err = claims.MarshalBinary()
+// grow returns dst with capacity for n more bytes.
+func grow(dst []byte, n int) []byte {
+	if cap(dst)-len(dst) >= n {
+		return dst
+	}
+
+	buf := make([]byte, len(dst), len(dst)+n)
+	copy(buf, dst)
+
+	return buf
+}
+
+// appendEncoded appends base64url encoded src to dst.
+func appendEncoded(dst, src []byte) []byte {
+	n := base64.RawURLEncoding.EncodedLen(len(src))
+	dst = grow(dst, n)
+	base64.RawURLEncoding.Encode(dst[len(dst):len(dst)+n], src)
+
+	return dst[:len(dst)+n]
+}
+
+func (b *TokenBuilder) encodeHeader(alg Algorithm) ([]byte, error) {
+	if b.isDefaultHeader() {
+		return b.encodeDefaultHeader(alg), nil
+	}
+
+	h := b.h
+	h.Alg = alg
+
+	buf, err := json.Marshal(h)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	names := make([]string, 0, len(b.params))
+	for name := range b.params {
+		if registeredHeaderParams[name] {
+			return nil, ErrRegisteredHeaderParam
+		}
+
+		names = append(names, name)
 	}
 
-	encoded = make([]byte, base64.RawURLEncoding.EncodedLen(len(raw)))
-	base64.RawURLEncoding.Encode(encoded, raw)
+	sort.Strings(names)
+
+	for _, name := range names {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(b.params[name])
+		if err != nil {
+			return nil, err
+		}
+
+		buf = append(buf[:len(buf)-1], ',')
+		buf = append(buf, key...)
+		buf = append(buf, ':')
+		buf = append(buf, value...)
+		buf = append(buf, '}')
+	}
+
+	encoded := make([]byte, base64.RawURLEncoding.EncodedLen(len(buf)))
+	base64.RawURLEncoding.Encode(encoded, buf)
+
+	return encoded, nil
+}
+
+// isDefaultHeader returns true if header has only "typ":"JWT" and optional kid parameters,
+// and kid does not require JSON escaping.
+func (b *TokenBuilder) isDefaultHeader() bool {
+	h := b.h
+
+	return h.Typ == "JWT" && h.Cty == "" && h.Jku == "" && h.Jwk == nil && h.X5u == "" && h.X5t == "" &&
+		h.X5tS256 == "" && h.X5c == nil && h.Crit == nil && len(b.params) == 0 && !needsEscape(h.Kid)
+}
+
+// needsEscape returns true if s must be escaped to be placed into JSON string.
+func needsEscape(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c == '"' || c == '\\' {
+			return true
+		}
+	}
 
-	return raw, encoded, nil
+	return false
 }
 
 // nolint: gocyclo
-func (b *TokenBuilder) encodeHeader(signer Signer) []byte {
-	if b.h.Kid != "" && b.h.Typ == "JWT" {
-		algID := signer.GetAlgorithmID()
+func (b *TokenBuilder) encodeDefaultHeader(alg Algorithm) []byte {
+	if b.h.Kid != "" {
+		algID := alg
 		algIDLen := len(algID)
 		kid := b.h.Kid
 		kidLen := len(kid)
@@ -122,7 +354,7 @@ func (b *TokenBuilder) encodeHeader(signer Signer) []byte {
 		return encoded
 	}
 
-	switch signer.GetAlgorithmID() {
+	switch alg {
 	case HS256:
 		return []byte("eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9")
 	case HS384:
@@ -145,8 +377,12 @@ func (b *TokenBuilder) encodeHeader(signer Signer) []byte {
 		return []byte("eyJhbGciOiJQUzI1NiIsInR5cCI6IkpXVCJ9")
 	case PS384:
 		return []byte("eyJhbGciOiJQUzM4NCIsInR5cCI6IkpXVCJ9")
+	case PS512:
+		return []byte("eyJhbGciOiJQUzUxMiIsInR5cCI6IkpXVCJ9")
+	case EdDSA:
+		return []byte("eyJhbGciOiJFZERTQSIsInR5cCI6IkpXVCJ9")
 	default:
-		algID := signer.GetAlgorithmID()
+		algID := alg
 		algIDLen := len(algID)
 		buf := make([]byte, 20+algIDLen+2)
 		copy(buf[:20], `{"typ":"JWT","alg":"`)
@@ -1,7 +1,9 @@
 package jwtee_test
 
 import (
+	"bytes"
 	"encoding"
+	"encoding/base64"
 	"encoding/json"
 	"github.com/furdarius/jwtee"
 	"github.com/furdarius/jwtee/signer"
@@ -63,3 +65,214 @@ func TestBuilder_Build(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_Build_Header(t *testing.T) {
+	key := jwtee.NewSharedSecretKey([]byte(`12345`))
+
+	tests := []struct {
+		desc    string
+		builder *jwtee.TokenBuilder
+		checker func(t *testing.T, parts *jwtee.DecodedParts, err error)
+	}{
+		{
+			desc:    "kid is kept in header",
+			builder: jwtee.NewTokenBuilder().WithKID("key-1"),
+			checker: func(t *testing.T, parts *jwtee.DecodedParts, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, jwtee.Header{Typ: "JWT", Alg: jwtee.HS256, Kid: "key-1"}, parts.Header())
+			},
+		},
+		{
+			desc:    "kid requiring escaping",
+			builder: jwtee.NewTokenBuilder().WithKID(`key"1`),
+			checker: func(t *testing.T, parts *jwtee.DecodedParts, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, `key"1`, parts.Header().Kid)
+			},
+		},
+		{
+			desc: "all registered parameters",
+			builder: jwtee.NewTokenBuilder().
+				WithType("at+jwt").
+				WithContentType("JWT").
+				WithKID("key-1").
+				WithJKU("https://example.com/jwks.json").
+				WithX5U("https://example.com/cert.pem").
+				WithX5T("dGh1bWJwcmludA").
+				WithX5TS256("c2hhMjU2LXRodW1icHJpbnQ").
+				WithX5C("MIIBCgKCAQEA").
+				WithCritical("exp").
+				WithHeaderParam("exp", 1516239022),
+			checker: func(t *testing.T, parts *jwtee.DecodedParts, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, jwtee.Header{
+					Typ:     "at+jwt",
+					Alg:     jwtee.HS256,
+					Cty:     "JWT",
+					Jku:     "https://example.com/jwks.json",
+					Kid:     "key-1",
+					X5u:     "https://example.com/cert.pem",
+					X5t:     "dGh1bWJwcmludA",
+					X5tS256: "c2hhMjU2LXRodW1icHJpbnQ",
+					X5c:     []string{"MIIBCgKCAQEA"},
+					Crit:    []string{"exp"},
+				}, parts.Header())
+			},
+		},
+		{
+			desc:    "custom parameters are signed",
+			builder: jwtee.NewTokenBuilder().WithType("dpop+jwt").WithHeaderParam("nonce", "abc").WithHeaderParam("b64", false),
+			checker: func(t *testing.T, parts *jwtee.DecodedParts, err error) {
+				assert.NoError(t, err)
+
+				encodedHeader := parts.Payload()[:bytes.IndexByte(parts.Payload(), '.')]
+				header, err := base64.RawURLEncoding.DecodeString(string(encodedHeader))
+				assert.NoError(t, err)
+				assert.JSONEq(t, `{"typ":"dpop+jwt","alg":"HS256","b64":false,"nonce":"abc"}`, string(header))
+			},
+		},
+		{
+			desc:    "registered parameter set as custom",
+			builder: jwtee.NewTokenBuilder().WithHeaderParam("alg", "none"),
+			checker: func(t *testing.T, parts *jwtee.DecodedParts, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			parts, err := test.builder.Build(testclaims{Name: "John Doe"}, signer.NewHS256(), key)
+			test.checker(t, parts, err)
+
+			if err != nil {
+				return
+			}
+
+			raw, _ := parts.MarshalText()
+			parsed, err := jwtee.NewJSONParser().Parse(raw)
+			assert.NoError(t, err)
+			assert.Equal(t, parts.Header(), parsed.Header())
+		})
+	}
+}
+
+func TestBuilder_AppendBuild(t *testing.T) {
+	key := jwtee.NewSharedSecretKey([]byte(`12345`))
+	claims := testclaims{Name: "John Doe"}
+
+	tests := []struct {
+		desc    string
+		builder *jwtee.TokenBuilder
+		signer  jwtee.Signer
+	}{
+		{
+			desc:    "default header",
+			builder: jwtee.NewTokenBuilder(),
+			signer:  signer.NewHS256(),
+		},
+		{
+			desc:    "header with kid",
+			builder: jwtee.NewTokenBuilder().WithKID("key-1"),
+			signer:  signer.NewHS512(),
+		},
+		{
+			desc:    "custom header",
+			builder: jwtee.NewTokenBuilder().WithType("at+jwt").WithHeaderParam("nonce", "abc"),
+			signer:  signer.NewHS384(),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			parts, err := test.builder.Build(claims, test.signer, key)
+			assert.NoError(t, err)
+
+			expected, _ := parts.MarshalText()
+
+			prefix := []byte("Bearer ")
+			dst := make([]byte, len(prefix), 512)
+			copy(dst, prefix)
+
+			token, err := test.builder.AppendBuild(dst, claims, test.signer, key)
+			assert.NoError(t, err)
+			assert.Equal(t, append(prefix, expected...), token)
+			assert.Equal(t, &dst[0], &token[0], "buffer with enough capacity must be reused")
+
+			token, err = test.builder.AppendBuild(nil, claims, test.signer, key)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, token)
+		})
+	}
+}
+
+func TestBuilder_AppendBuild_HeaderChange(t *testing.T) {
+	key := jwtee.NewSharedSecretKey([]byte(`12345`))
+	builder := jwtee.NewTokenBuilder()
+
+	_, err := builder.AppendBuild(nil, testclaims{}, signer.NewHS256(), key)
+	assert.NoError(t, err)
+
+	token, err := builder.WithKID("key-2").AppendBuild(nil, testclaims{}, signer.NewHS256(), key)
+	assert.NoError(t, err)
+
+	parts, err := jwtee.NewJSONParser().Parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "key-2", parts.Header().Kid)
+}
+
+func BenchmarkTokenBuilder_Build(b *testing.B) {
+	key := jwtee.NewSharedSecretKey([]byte(`12345`))
+	builder := jwtee.NewTokenBuilder().WithKID("key-1")
+	s := signer.NewHS256()
+	claims := testclaims{Name: "John Doe"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := builder.Build(claims, s, key)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTokenBuilder_AppendBuild(b *testing.B) {
+	key := jwtee.NewSharedSecretKey([]byte(`12345`))
+	s := signer.NewHS256()
+	claims := testclaims{Name: "John Doe"}
+
+	benchmarks := []struct {
+		desc    string
+		builder *jwtee.TokenBuilder
+	}{
+		{"default header", jwtee.NewTokenBuilder()},
+		{"header with kid", jwtee.NewTokenBuilder().WithKID("key-1")},
+		{"custom header", jwtee.NewTokenBuilder().WithType("at+jwt").WithHeaderParam("nonce", "abc")},
+	}
+
+	for _, bm := range benchmarks {
+		bm := bm
+
+		b.Run(bm.desc, func(b *testing.B) {
+			buf := make([]byte, 0, 512)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				var err error
+
+				buf, err = bm.builder.AppendBuild(buf[:0], claims, s, key)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
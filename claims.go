@@ -1,7 +1,15 @@
 package jwtee
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrClaimMissed indicates that claim is not present in the ClaimSet.
+	ErrClaimMissed = errors.New("claim missed")
 )
 
 // RegisteredClaims are the IANA registered “JSON Web Token Claims”.
@@ -16,7 +24,7 @@ type RegisteredClaims struct {
 	//   special case when the JWT has one audience, the "aud" value MAY be a
 	//   single case-sensitive string containing a StringOrURI value.  The
 	//   interpretation of audience values is generally application specific.
-	Aud []string `json:"aud,omitempty"`
+	Aud Audience `json:"aud,omitempty"`
 
 	//   The "exp" (expiration time) claim identifies the expiration time on
 	//   or after which the JWT MUST NOT be accepted for processing.  The
@@ -68,6 +76,17 @@ type RegisteredClaims struct {
 	Sub string `json:"sub,omitempty"`
 }
 
+// Claims used to access RegisteredClaims of user defined claims.
+// It is implemented by any struct embedding RegisteredClaims.
+type Claims interface {
+	Registered() RegisteredClaims
+}
+
+// Registered implements Claims.
+func (c RegisteredClaims) Registered() RegisteredClaims {
+	return c
+}
+
 // IsPermittedFor returns true if claims is allowed to be used by the audience.
 func (c RegisteredClaims) IsPermittedFor(audience string) bool {
 	for _, tokenAudience := range c.Aud {
@@ -132,3 +151,76 @@ func (c RegisteredClaims) IsExpired(now time.Time) bool {
 
 	return expireAt.Before(now)
 }
+
+// ClaimSet stores all top-level claims by name, including private ones.
+type ClaimSet map[string]json.RawMessage
+
+// ParseClaimSet decodes ClaimSet from raw claims.
+func ParseClaimSet(raw json.RawMessage) (ClaimSet, error) {
+	var set ClaimSet
+
+	err := json.Unmarshal(raw, &set)
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// Has returns true if claim is present.
+func (s ClaimSet) Has(name string) bool {
+	_, ok := s[name]
+
+	return ok
+}
+
+// String returns value of the string claim.
+// False returns if claim is missing or is not a string.
+func (s ClaimSet) String(name string) (string, bool) {
+	raw, ok := s[name]
+	if !ok {
+		return "", false
+	}
+
+	var value string
+
+	err := json.Unmarshal(raw, &value)
+	if err != nil {
+		return "", false
+	}
+
+	return value, true
+}
+
+// Strings returns values of the claim holding either string or array of strings.
+// False returns if claim is missing or has another type.
+func (s ClaimSet) Strings(name string) ([]string, bool) {
+	raw, ok := s[name]
+	if !ok {
+		return nil, false
+	}
+
+	var values []string
+
+	err := json.Unmarshal(raw, &values)
+	if err == nil {
+		return values, true
+	}
+
+	value, ok := s.String(name)
+	if !ok {
+		return nil, false
+	}
+
+	return []string{value}, true
+}
+
+// Unmarshal decodes value of the claim into v.
+func (s ClaimSet) Unmarshal(name string, v interface{}) error {
+	raw, ok := s[name]
+	if !ok {
+		return ErrClaimMissed
+	}
+
+	return json.Unmarshal(raw, v)
+}
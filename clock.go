@@ -0,0 +1,34 @@
+package jwtee
+
+import (
+	"time"
+)
+
+// Clock used to get current time in time-based checks.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock returns current system time.
+type SystemClock struct{}
+
+// Now implements Clock.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always returns the same instant.
+// It is useful in tests and to validate tokens "as of" a given time.
+type FixedClock struct {
+	t time.Time
+}
+
+// NewFixedClock returns new instance of FixedClock frozen at t.
+func NewFixedClock(t time.Time) FixedClock {
+	return FixedClock{t}
+}
+
+// Now implements Clock.
+func (c FixedClock) Now() time.Time {
+	return c.t
+}
@@ -0,0 +1,125 @@
+package constraint
+
+import (
+	"errors"
+
+	"github.com/furdarius/jwtee"
+)
+
+// Block represents private claims constraints errors.
+var (
+	ErrClaimMismatch   = errors.New("token claim does not equal the expected value")
+	ErrClaimNotAllowed = errors.New("token claim is not one of the allowed values")
+	ErrClaimNotContain = errors.New("token claim does not contain the expected value")
+)
+
+// ClaimEquals checks if string claim equals the expected value.
+type ClaimEquals struct {
+	name  string
+	value string
+}
+
+// NewClaimEquals returns new instance of ClaimEquals.
+func NewClaimEquals(name, value string) *ClaimEquals {
+	return &ClaimEquals{name, value}
+}
+
+// Validate implements Constraint.
+func (c *ClaimEquals) Validate(claims jwtee.RegisteredClaims) (err error) {
+	return jwtee.ErrClaimSetRequired
+}
+
+// ValidateClaimSet implements ClaimSetConstraint.
+func (c *ClaimEquals) ValidateClaimSet(claims jwtee.RegisteredClaims, set jwtee.ClaimSet) (err error) {
+	value, ok := set.String(c.name)
+	if !ok {
+		return jwtee.ErrClaimMissed
+	}
+
+	if value != c.value {
+		return ErrClaimMismatch
+	}
+
+	return nil
+}
+
+// ClaimOneOf checks if string claim is one of the allowed values.
+type ClaimOneOf struct {
+	name   string
+	values []string
+}
+
+// NewClaimOneOf returns new instance of ClaimOneOf.
+func NewClaimOneOf(name string, values ...string) *ClaimOneOf {
+	return &ClaimOneOf{name, values}
+}
+
+// Validate implements Constraint.
+func (c *ClaimOneOf) Validate(claims jwtee.RegisteredClaims) (err error) {
+	return jwtee.ErrClaimSetRequired
+}
+
+// ValidateClaimSet implements ClaimSetConstraint.
+func (c *ClaimOneOf) ValidateClaimSet(claims jwtee.RegisteredClaims, set jwtee.ClaimSet) (err error) {
+	value, ok := set.String(c.name)
+	if !ok {
+		return jwtee.ErrClaimMissed
+	}
+
+	for _, allowed := range c.values {
+		if value == allowed {
+			return nil
+		}
+	}
+
+	return ErrClaimNotAllowed
+}
+
+// ClaimContains checks if claim holding string or array of strings contains all of the expected values.
+type ClaimContains struct {
+	name   string
+	values []string
+}
+
+// NewClaimContains returns new instance of ClaimContains.
+func NewClaimContains(name string, values ...string) *ClaimContains {
+	return &ClaimContains{name, values}
+}
+
+// Validate implements Constraint.
+func (c *ClaimContains) Validate(claims jwtee.RegisteredClaims) (err error) {
+	return jwtee.ErrClaimSetRequired
+}
+
+// ValidateClaimSet implements ClaimSetConstraint.
+func (c *ClaimContains) ValidateClaimSet(claims jwtee.RegisteredClaims, set jwtee.ClaimSet) (err error) {
+	values, ok := set.Strings(c.name)
+	if !ok {
+		return jwtee.ErrClaimMissed
+	}
+
+	if !containsAll(values, c.values) {
+		return ErrClaimNotContain
+	}
+
+	return nil
+}
+
+func containsAll(values, expected []string) bool {
+	for _, e := range expected {
+		found := false
+
+		for _, v := range values {
+			if v == e {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
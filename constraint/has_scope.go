@@ -0,0 +1,46 @@
+package constraint
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/furdarius/jwtee"
+)
+
+// Block represents HasScope constraint errors.
+var (
+	ErrTokenInsufficientScope = errors.New("token has insufficient scope")
+)
+
+// scopeClaim is the name of space-delimited scope claim.
+// @see https://tools.ietf.org/html/rfc8693#section-4.2
+const scopeClaim = "scope"
+
+// HasScope checks if space-delimited scope claim contains all of the expected scopes.
+type HasScope struct {
+	scopes []string
+}
+
+// NewHasScope returns new instance of HasScope.
+func NewHasScope(scopes ...string) *HasScope {
+	return &HasScope{scopes}
+}
+
+// Validate implements Constraint.
+func (c *HasScope) Validate(claims jwtee.RegisteredClaims) (err error) {
+	return jwtee.ErrClaimSetRequired
+}
+
+// ValidateClaimSet implements ClaimSetConstraint.
+func (c *HasScope) ValidateClaimSet(claims jwtee.RegisteredClaims, set jwtee.ClaimSet) (err error) {
+	scope, ok := set.String(scopeClaim)
+	if !ok {
+		return ErrTokenInsufficientScope
+	}
+
+	if !containsAll(strings.Fields(scope), c.scopes) {
+		return ErrTokenInsufficientScope
+	}
+
+	return nil
+}
@@ -9,20 +9,54 @@ import (
 
 // Block represents ValidAt constraint errors.
 var (
-	ErrTokenExpired   = errors.New("token is expired")
-	ErrTokenNotBefore = errors.New("token cannot be used yet")
-	ErrTokenNotIssued = errors.New("token was issued in the future")
+	ErrTokenExpired         = errors.New("token is expired")
+	ErrTokenNotBefore       = errors.New("token cannot be used yet")
+	ErrTokenNotIssued       = errors.New("token was issued in the future")
+	ErrTokenTooOld          = errors.New("token was issued too long ago")
+	ErrTokenLifetimeTooLong = errors.New("token lifetime exceeds the maximum")
+	ErrExpirationMissed     = errors.New("token has no expiration time")
+	ErrNotBeforeMissed      = errors.New("token has no not before time")
+	ErrIssuedAtMissed       = errors.New("token has no issued at time")
+)
+
+// ClaimPolicy describes how ValidAt treats time claim.
+type ClaimPolicy int
+
+// ClaimPolicy constants represents available policies.
+const (
+	// ClaimOptional checks claim only if it is present.
+	ClaimOptional ClaimPolicy = iota
+
+	// ClaimRequired rejects token without the claim.
+	ClaimRequired
+
+	// ClaimIgnored never checks claim.
+	ClaimIgnored
 )
 
 // ValidAt checks if Claims is valid on current time.
+// By default exp, nbf and iat claims are optional.
 type ValidAt struct {
 	// leeway is time gap after now when token will not be expired.
 	leeway time.Duration
+
+	// clock provides current time, system time if nil.
+	clock jwtee.Clock
+
+	exp ClaimPolicy
+	nbf ClaimPolicy
+	iat ClaimPolicy
+
+	// maxAge is maximum time passed since token issuing, zero means unlimited.
+	maxAge time.Duration
+
+	// maxLifetime is maximum difference between exp and iat, zero means unlimited.
+	maxLifetime time.Duration
 }
 
 // NewValidAt returns new instance of ValidAt.
 func NewValidAt() *ValidAt {
-	return &ValidAt{}
+	return &ValidAt{clock: jwtee.SystemClock{}}
 }
 
 // WithLeeway setup leeway for ValidAt Constraint
@@ -32,9 +66,61 @@ func (c *ValidAt) WithLeeway(leeway time.Duration) *ValidAt {
 	return c
 }
 
+// WithClock setup clock used to get current time for ValidAt Constraint.
+// Nil clock means system time.
+func (c *ValidAt) WithClock(clock jwtee.Clock) *ValidAt {
+	c.clock = clock
+
+	return c
+}
+
+// WithExpiration setup policy for "exp" claim.
+func (c *ValidAt) WithExpiration(policy ClaimPolicy) *ValidAt {
+	c.exp = policy
+
+	return c
+}
+
+// WithNotBefore setup policy for "nbf" claim.
+func (c *ValidAt) WithNotBefore(policy ClaimPolicy) *ValidAt {
+	c.nbf = policy
+
+	return c
+}
+
+// WithIssuedAt setup policy for "iat" claim.
+func (c *ValidAt) WithIssuedAt(policy ClaimPolicy) *ValidAt {
+	c.iat = policy
+
+	return c
+}
+
+// WithMaxAge setup maximum time passed since token issuing.
+// Token without "iat" claim is rejected with ErrIssuedAtMissed.
+// It is not checked if "iat" claim is ignored.
+func (c *ValidAt) WithMaxAge(maxAge time.Duration) *ValidAt {
+	c.maxAge = maxAge
+
+	return c
+}
+
+// WithMaxLifetime setup maximum difference between "exp" and "iat" claims.
+// Token without any of these claims is rejected with the claim missing error.
+// It is not checked if "iat" or "exp" claim is ignored.
+func (c *ValidAt) WithMaxLifetime(maxLifetime time.Duration) *ValidAt {
+	c.maxLifetime = maxLifetime
+
+	return c
+}
+
 // Validate implements Constraint.
 func (c *ValidAt) Validate(claims jwtee.RegisteredClaims) (err error) {
-	now := time.Now()
+	clock := c.clock
+	if clock == nil {
+		clock = jwtee.SystemClock{}
+	}
+
+	now := clock.Now()
 
 	err = c.checkIssueTime(claims, now.Add(c.leeway))
 	if err != nil {
@@ -51,10 +137,32 @@ func (c *ValidAt) Validate(claims jwtee.RegisteredClaims) (err error) {
 		return err
 	}
 
+	err = c.checkMaxAge(claims, now.Add(-c.leeway))
+	if err != nil {
+		return err
+	}
+
+	err = c.checkMaxLifetime(claims)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (c *ValidAt) checkExpiration(claims jwtee.RegisteredClaims, now time.Time) error {
+	if c.exp == ClaimIgnored {
+		return nil
+	}
+
+	if claims.Exp == 0 {
+		if c.exp == ClaimRequired {
+			return ErrExpirationMissed
+		}
+
+		return nil
+	}
+
 	if claims.IsExpired(now) {
 		return ErrTokenExpired
 	}
@@ -63,6 +171,18 @@ func (c *ValidAt) checkExpiration(claims jwtee.RegisteredClaims, now time.Time)
 }
 
 func (c *ValidAt) checkMinimumTime(claims jwtee.RegisteredClaims, now time.Time) error {
+	if c.nbf == ClaimIgnored {
+		return nil
+	}
+
+	if claims.Nbf == 0 {
+		if c.nbf == ClaimRequired {
+			return ErrNotBeforeMissed
+		}
+
+		return nil
+	}
+
 	if !claims.HasBeenCrossedNotBefore(now) {
 		return ErrTokenNotBefore
 	}
@@ -71,9 +191,57 @@ func (c *ValidAt) checkMinimumTime(claims jwtee.RegisteredClaims, now time.Time)
 }
 
 func (c *ValidAt) checkIssueTime(claims jwtee.RegisteredClaims, now time.Time) error {
+	if c.iat == ClaimIgnored {
+		return nil
+	}
+
+	if claims.Iat == 0 {
+		if c.iat == ClaimRequired {
+			return ErrIssuedAtMissed
+		}
+
+		return nil
+	}
+
 	if !claims.HasBeenIssuedBefore(now) {
 		return ErrTokenNotIssued
 	}
 
 	return nil
 }
+
+func (c *ValidAt) checkMaxAge(claims jwtee.RegisteredClaims, now time.Time) error {
+	if c.maxAge == 0 || c.iat == ClaimIgnored {
+		return nil
+	}
+
+	if claims.Iat == 0 {
+		return ErrIssuedAtMissed
+	}
+
+	if claims.Iat.Time().Add(c.maxAge).Before(now) {
+		return ErrTokenTooOld
+	}
+
+	return nil
+}
+
+func (c *ValidAt) checkMaxLifetime(claims jwtee.RegisteredClaims) error {
+	if c.maxLifetime == 0 || c.iat == ClaimIgnored || c.exp == ClaimIgnored {
+		return nil
+	}
+
+	if claims.Iat == 0 {
+		return ErrIssuedAtMissed
+	}
+
+	if claims.Exp == 0 {
+		return ErrExpirationMissed
+	}
+
+	if claims.Exp.Time().Sub(claims.Iat.Time()) > c.maxLifetime {
+		return ErrTokenLifetimeTooLong
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package constraint_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/constraint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidAt_Validate(t *testing.T) {
+	now := time.Unix(1516239022, 0)
+
+	tests := []struct {
+		desc       string
+		claims     jwtee.RegisteredClaims
+		constraint *constraint.ValidAt
+		checker    func(t *testing.T, err error)
+	}{
+		{
+			desc:       "successful validation as of fixed time",
+			claims:     jwtee.RegisteredClaims{Iat: 1516239000, Nbf: 1516239000, Exp: 1516239100},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:       "failed on expired token",
+			claims:     jwtee.RegisteredClaims{Iat: 1516239000, Exp: 1516239010},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, constraint.ErrTokenExpired, err)
+			},
+		},
+		{
+			desc:       "successful validation of expired token with leeway",
+			claims:     jwtee.RegisteredClaims{Iat: 1516239000, Exp: 1516239010},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithLeeway(time.Minute),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:       "failed on not yet valid token",
+			claims:     jwtee.RegisteredClaims{Iat: 1516239000, Nbf: 1516239100},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, constraint.ErrTokenNotBefore, err)
+			},
+		},
+		{
+			desc:       "failed on token issued in the future",
+			claims:     jwtee.RegisteredClaims{Iat: 1516239100},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, constraint.ErrTokenNotIssued, err)
+			},
+		},
+		{
+			desc:       "successful validation of token without iat by default",
+			claims:     jwtee.RegisteredClaims{Exp: 1516239100},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:       "failed on missing required exp",
+			claims:     jwtee.RegisteredClaims{Iat: 1516239000},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithExpiration(constraint.ClaimRequired),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, constraint.ErrExpirationMissed, err)
+			},
+		},
+		{
+			desc:       "failed on missing required nbf",
+			claims:     jwtee.RegisteredClaims{Iat: 1516239000},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithNotBefore(constraint.ClaimRequired),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, constraint.ErrNotBeforeMissed, err)
+			},
+		},
+		{
+			desc:       "failed on missing required iat",
+			claims:     jwtee.RegisteredClaims{Exp: 1516239100},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithIssuedAt(constraint.ClaimRequired),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, constraint.ErrIssuedAtMissed, err)
+			},
+		},
+		{
+			desc:       "successful validation of expired token with ignored exp",
+			claims:     jwtee.RegisteredClaims{Exp: 1516239010},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithExpiration(constraint.ClaimIgnored),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:       "failed on token older than max age",
+			claims:     jwtee.RegisteredClaims{Iat: 1516238000},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithMaxAge(time.Minute),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, constraint.ErrTokenTooOld, err)
+			},
+		},
+		{
+			desc:       "failed on max age check of token without iat",
+			claims:     jwtee.RegisteredClaims{Exp: 1516239100},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithMaxAge(time.Minute),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, constraint.ErrIssuedAtMissed, err)
+			},
+		},
+		{
+			desc:       "successful validation of token with allowed lifetime",
+			claims:     jwtee.RegisteredClaims{Iat: 1516239000, Exp: 1516239060},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithMaxLifetime(time.Minute),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:       "failed on token with too long lifetime",
+			claims:     jwtee.RegisteredClaims{Iat: 1516239000, Exp: 1516242600},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithMaxLifetime(time.Minute),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, constraint.ErrTokenLifetimeTooLong, err)
+			},
+		},
+		{
+			desc:       "successful validation of token without iat when iat is ignored",
+			claims:     jwtee.RegisteredClaims{Exp: 1516239060},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithIssuedAt(constraint.ClaimIgnored).WithMaxAge(time.Minute).WithMaxLifetime(time.Minute),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:       "successful validation of old token when iat is ignored",
+			claims:     jwtee.RegisteredClaims{Iat: 1516230000},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithIssuedAt(constraint.ClaimIgnored).WithMaxAge(time.Minute),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:       "successful validation of token without exp when exp is ignored",
+			claims:     jwtee.RegisteredClaims{Iat: 1516239000},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithExpiration(constraint.ClaimIgnored).WithMaxLifetime(time.Minute),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:       "failed on token without exp with max lifetime",
+			claims:     jwtee.RegisteredClaims{Iat: 1516239000},
+			constraint: constraint.NewValidAt().WithClock(jwtee.NewFixedClock(now)).WithMaxLifetime(time.Minute),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, constraint.ErrExpirationMissed, err)
+			},
+		},
+		{
+			desc:       "zero value falls back to system time",
+			claims:     jwtee.RegisteredClaims{Exp: jwtee.TimestampFromNow(time.Minute)},
+			constraint: &constraint.ValidAt{},
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:       "nil clock falls back to system time",
+			claims:     jwtee.RegisteredClaims{Exp: 1516239010},
+			constraint: constraint.NewValidAt().WithClock(nil),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, constraint.ErrTokenExpired, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			err := tt.constraint.Validate(tt.claims)
+
+			tt.checker(t, err)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package jwtee
+
+// ErrorKind classifies failures of token processing.
+type ErrorKind int
+
+// ErrorKind constants represents available kinds of failures.
+const (
+	// KindMalformed indicates that token cannot be decoded.
+	KindMalformed ErrorKind = iota + 1
+
+	// KindUnverifiable indicates that token signature cannot be checked,
+	// e.g. algorithm is not expected or key is unknown.
+	KindUnverifiable
+
+	// KindSignatureInvalid indicates that token signature does not match.
+	KindSignatureInvalid
+
+	// KindClaimsInvalid indicates that token claims does not satisfy constraints.
+	KindClaimsInvalid
+)
+
+// String implements fmt.Stringer.
+func (k ErrorKind) String() string {
+	switch k {
+	case KindMalformed:
+		return "malformed token"
+	case KindUnverifiable:
+		return "unverifiable token"
+	case KindSignatureInvalid:
+		return "token signature is invalid"
+	case KindClaimsInvalid:
+		return "token claims are invalid"
+	default:
+		return "invalid token"
+	}
+}
+
+// Segment describes part of the token.
+type Segment string
+
+// Segment constants represents parts of the token.
+const (
+	// SegmentToken used when failure is not related to particular part.
+	SegmentToken     Segment = ""
+	SegmentHeader    Segment = "header"
+	SegmentClaims    Segment = "claims"
+	SegmentSignature Segment = "signature"
+)
+
+// TokenError describes failure of token processing.
+// Use errors.Is with ErrKindMalformed, ErrKindUnverifiable, ErrKindSignatureInvalid or ErrKindClaimsInvalid
+// to check the kind of failure and errors.Is with sentinel errors to check the cause.
+type TokenError struct {
+	// Kind is the class of failure.
+	Kind ErrorKind
+
+	// Segment is the failed part of the token.
+	Segment Segment
+
+	// Err is the underlying cause.
+	Err error
+}
+
+// Block represents TokenError kinds, matched by errors.Is regardless of segment and cause.
+var (
+	ErrKindMalformed        = &TokenError{Kind: KindMalformed}
+	ErrKindUnverifiable     = &TokenError{Kind: KindUnverifiable}
+	ErrKindSignatureInvalid = &TokenError{Kind: KindSignatureInvalid}
+	ErrKindClaimsInvalid    = &TokenError{Kind: KindClaimsInvalid}
+)
+
+// Error implements error.
+func (e *TokenError) Error() string {
+	msg := e.Kind.String()
+
+	if e.Segment != SegmentToken {
+		msg += " (" + string(e.Segment) + ")"
+	}
+
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying cause.
+func (e *TokenError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is a kind sentinel of the error.
+// Target segment, if set, must match too.
+func (e *TokenError) Is(target error) bool {
+	t, ok := target.(*TokenError)
+	if !ok || t.Err != nil {
+		return false
+	}
+
+	return t.Kind == e.Kind && (t.Segment == SegmentToken || t.Segment == e.Segment)
+}
+
+// newTokenError wraps err into TokenError, unless it is TokenError already.
+func newTokenError(kind ErrorKind, segment Segment, err error) error {
+	if _, ok := err.(*TokenError); ok {
+		return err
+	}
+
+	return &TokenError{kind, segment, err}
+}
@@ -0,0 +1,83 @@
+package jwtee_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenError(t *testing.T) {
+	key := jwtee.NewSharedSecretKey([]byte(`secret`))
+	token := buildToken(t, jwtee.NewTokenBuilder(), signer.NewHS256(), key)
+
+	tests := []struct {
+		desc    string
+		jwt     []byte
+		parser  jwtee.Parser
+		checker func(t *testing.T, err error)
+	}{
+		{
+			desc:   "malformed header",
+			jwt:    []byte(`!!!.e30.c2ln`),
+			parser: jwtee.NewJSONParser(),
+			checker: func(t *testing.T, err error) {
+				var tokenErr *jwtee.TokenError
+				assert.True(t, errors.As(err, &tokenErr))
+				assert.Equal(t, jwtee.KindMalformed, tokenErr.Kind)
+				assert.Equal(t, jwtee.SegmentHeader, tokenErr.Segment)
+
+				var cause base64.CorruptInputError
+				assert.True(t, errors.As(err, &cause))
+
+				assert.True(t, errors.Is(err, jwtee.ErrKindMalformed))
+				assert.True(t, errors.Is(err, &jwtee.TokenError{Kind: jwtee.KindMalformed, Segment: jwtee.SegmentHeader}))
+				assert.False(t, errors.Is(err, &jwtee.TokenError{Kind: jwtee.KindMalformed, Segment: jwtee.SegmentClaims}))
+				assert.False(t, errors.Is(err, jwtee.ErrKindSignatureInvalid))
+			},
+		},
+		{
+			desc:   "missed part",
+			jwt:    []byte(`e30`),
+			parser: jwtee.NewJSONParser(),
+			checker: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, jwtee.ErrKindMalformed))
+				assert.True(t, errors.Is(err, jwtee.ErrPartMissed))
+				assert.Equal(t, "malformed token: one of token parts missed", err.Error())
+			},
+		},
+		{
+			desc:   "invalid signature",
+			jwt:    token,
+			parser: jwtee.NewVerifyingParser(jwtee.NewJSONParser(), jwtee.NewPartsVerifier(signer.NewHS256(), jwtee.NewSharedSecretKey([]byte(`other`)))),
+			checker: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, jwtee.ErrKindSignatureInvalid))
+				assert.True(t, errors.Is(err, jwtee.ErrInvalidSignature))
+				assert.False(t, errors.Is(err, jwtee.ErrKindUnverifiable))
+				assert.Equal(t, "token signature is invalid (signature): invalid signature", err.Error())
+			},
+		},
+		{
+			desc:   "unverifiable token",
+			jwt:    token,
+			parser: jwtee.NewVerifyingParser(jwtee.NewJSONParser(), jwtee.NewPartsVerifier(signer.NewHS512(), key)),
+			checker: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, jwtee.ErrKindUnverifiable))
+				assert.True(t, errors.Is(err, jwtee.ErrAlgorithmMismatch))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := tt.parser.Parse(tt.jwt)
+
+			tt.checker(t, err)
+		})
+	}
+}
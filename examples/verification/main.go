@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -25,38 +25,30 @@ func main() {
 	hmacSigner := signer.NewHS256()
 	key := jwtee.NewSharedSecretKey(secret)
 	verifier := jwtee.NewPartsVerifier(hmacSigner, key)
-
-	jsonParser := jwtee.NewJSONParser()
-	verifyingParser := jwtee.NewVerifyingParser(jsonParser, verifier)
-
-	tokenParts, err := verifyingParser.Parse(token)
-
-	if err == jwtee.ErrInvalidSignature {
-		log.Fatal("token has invalid signature")
-	}
-
-	if err != nil {
-		log.Fatalf("failed to parse JWT string: %v", err)
-	}
+	processor := jwtee.NewProcessor(verifier)
 
 	var claims myclaims
-	err = json.Unmarshal(tokenParts.RawClaims(), &claims)
-	if err != nil {
-		log.Fatalf("failed to unmarshal claims: %v", err)
-	}
-
-	claimsValidator := jwtee.NewClaimsValidator()
-	errs := claimsValidator.Validate(claims.RegisteredClaims,
+	_, err := processor.Process(token, &claims,
 		constraint.NewValidAt().WithLeeway(1*time.Minute),
 		constraint.NewRelatedTo("myservice"),
 	)
-	if errs != nil {
+
+	var processingErr *jwtee.ProcessingError
+	if errors.As(err, &processingErr) && processingErr.Stage == jwtee.StageValidate {
 		log.Println("claims is not valid:")
-		for _, constraintErr := range errs {
+		for _, constraintErr := range processingErr.Errs {
 			log.Println("  ", constraintErr)
 		}
 		os.Exit(1)
 	}
 
+	if errors.Is(err, jwtee.ErrInvalidSignature) {
+		log.Fatal("token has invalid signature")
+	}
+
+	if err != nil {
+		log.Fatalf("failed to process JWT string: %v", err)
+	}
+
 	fmt.Println("Name from claims:", claims.Name)
 }
@@ -1,6 +1,14 @@
 module github.com/furdarius/jwtee
 
+go 1.27.1
+
 require (
 	github.com/pkg/errors v0.8.1
 	github.com/stretchr/testify v1.3.0
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/objx v0.1.0 // indirect
+)
@@ -1,5 +1,9 @@
 package jwtee
 
+import (
+	"encoding/json"
+)
+
 // Algorithm describes algorithms supported for signing/verifying.
 type Algorithm string
 
@@ -16,8 +20,20 @@ const (
 	ES512 Algorithm = "ES512"
 	PS256 Algorithm = "PS256"
 	PS384 Algorithm = "PS384"
+	PS512 Algorithm = "PS512"
+	EdDSA Algorithm = "EdDSA"
+
+	// None is used for unsecured JWT, which is not integrity protected.
+	// @see https://tools.ietf.org/html/rfc7519#section-6
+	None Algorithm = "none"
 )
 
+// registeredHeaderParams contains names of Header fields.
+var registeredHeaderParams = map[string]bool{
+	"typ": true, "alg": true, "cty": true, "jku": true, "jwk": true, "kid": true,
+	"x5u": true, "x5t": true, "x5t#S256": true, "x5c": true, "crit": true,
+}
+
 // Header stores JWT header data.
 type Header struct {
 	// The type of JWS: it can only be "JWT" here
@@ -26,7 +42,7 @@ type Header struct {
 
 	// The algorithm used
 	// @see https://tools.ietf.org/html/rfc7515#section-4.1.1
-	Alg Algorithm `json:"alg"`
+	Alg Algorithm `json:"alg,omitempty"`
 
 	// Content type
 	// @see https://tools.ietf.org/html/rfc7519#section-5.2
@@ -36,6 +52,10 @@ type Header struct {
 	// @see https://tools.ietf.org/html/rfc7515#section-4.1.2
 	Jku string `json:"jku,omitempty"`
 
+	// JSON Web Key, kept raw to be decoded with jwk package
+	// @see https://tools.ietf.org/html/rfc7515#section-4.1.3
+	Jwk json.RawMessage `json:"jwk,omitempty"`
+
 	// Key ID
 	// @see https://tools.ietf.org/html/rfc7515#section-4.1.4
 	Kid string `json:"kid,omitempty"`
@@ -47,4 +67,33 @@ type Header struct {
 	// X.509 certificate thumbprint
 	// @see https://tools.ietf.org/html/rfc7515#section-4.1.7
 	X5t string `json:"x5t,omitempty"`
+
+	// X.509 certificate SHA-256 thumbprint
+	// @see https://tools.ietf.org/html/rfc7515#section-4.1.8
+	X5tS256 string `json:"x5t#S256,omitempty"`
+
+	// X.509 certificate chain of base64 (not base64url) encoded DER certificates
+	// @see https://tools.ietf.org/html/rfc7515#section-4.1.6
+	X5c []string `json:"x5c,omitempty"`
+
+	// Critical extension parameters which must be understood and processed
+	// @see https://tools.ietf.org/html/rfc7515#section-4.1.11
+	Crit []string `json:"crit,omitempty"`
+}
+
+// clone returns deep copy of the header.
+func (h Header) clone() Header {
+	if h.Jwk != nil {
+		h.Jwk = append(json.RawMessage(nil), h.Jwk...)
+	}
+
+	if h.X5c != nil {
+		h.X5c = append([]string(nil), h.X5c...)
+	}
+
+	if h.Crit != nil {
+		h.Crit = append([]string(nil), h.Crit...)
+	}
+
+	return h
 }
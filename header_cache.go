@@ -0,0 +1,65 @@
+package jwtee
+
+import (
+	"sync"
+)
+
+// DefaultHeaderCacheSize is number of decoded headers cached by JSONParser by default.
+const DefaultHeaderCacheSize = 64
+
+// maxCachedHeaderLength limits length of encoded header stored in cache.
+// Headers come from unverified tokens, so large ones are not cached
+// to keep memory used by forged tokens bounded.
+const maxCachedHeaderLength = 1024
+
+// cachedHeader stores decoded header.
+type cachedHeader struct {
+	header Header
+	raw    []byte
+}
+
+// headerCache stores decoded headers keyed by encoded header segment.
+// Most tokens share identical headers, so it saves header decoding.
+type headerCache struct {
+	mu      sync.RWMutex
+	size    int
+	entries map[string]cachedHeader
+}
+
+func newHeaderCache(size int) *headerCache {
+	return &headerCache{
+		size:    size,
+		entries: make(map[string]cachedHeader, size),
+	}
+}
+
+// get returns decoded header for the encoded segment.
+func (c *headerCache) get(encoded []byte) (cachedHeader, bool) {
+	c.mu.RLock()
+	h, ok := c.entries[string(encoded)]
+	c.mu.RUnlock()
+
+	return h, ok
+}
+
+// put stores decoded header for the encoded segment.
+// If cache is full then an arbitrary entry is evicted.
+// Headers longer than maxCachedHeaderLength are not stored.
+func (c *headerCache) put(encoded []byte, h Header, raw []byte) {
+	if len(encoded) > maxCachedHeaderLength {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.size {
+		for key := range c.entries {
+			delete(c.entries, key)
+
+			break
+		}
+	}
+
+	c.entries[string(encoded)] = cachedHeader{h, append([]byte(nil), raw...)}
+}
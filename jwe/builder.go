@@ -0,0 +1,105 @@
+package jwe
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/furdarius/jwtee"
+	"github.com/pkg/errors"
+)
+
+// Builder used to build encrypted token in compact serialization.
+type Builder struct {
+	h Header
+}
+
+// NewBuilder returns new instance of Builder.
+func NewBuilder(alg KeyAlgorithm, enc ContentEncryption) *Builder {
+	return &Builder{
+		h: Header{
+			Alg: alg,
+			Enc: enc,
+		},
+	}
+}
+
+// WithKID used to setup the kid (key ID) Header Parameter.
+func (b *Builder) WithKID(kid string) *Builder {
+	b.h.Kid = kid
+
+	return b
+}
+
+// WithType used to setup the typ (type) Header Parameter.
+func (b *Builder) WithType(typ string) *Builder {
+	b.h.Typ = typ
+
+	return b
+}
+
+// WithContentType used to setup the cty (content type) Header Parameter.
+func (b *Builder) WithContentType(cty string) *Builder {
+	b.h.Cty = cty
+
+	return b
+}
+
+// Build used to encrypt plaintext for the recipient key.
+// Key is recipient public key, shared symmetric key or content encryption key for "dir".
+func (b *Builder) Build(plaintext encoding.BinaryMarshaler, key jwtee.Key) (*DecodedParts, error) {
+	raw, err := plaintext.MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode plaintext")
+	}
+
+	cipher, err := contentCipherFor(b.h.Enc)
+	if err != nil {
+		return nil, err
+	}
+
+	h := b.h
+
+	cek, encryptedKey, err := encryptKey(&h, cipher.keySize, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt content encryption key")
+	}
+
+	rawHeader, err := json.Marshal(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode header")
+	}
+
+	encodedHeader := encodeSegment(rawHeader)
+
+	iv, ciphertext, tag, err := cipher.encrypt(cek, raw, encodedHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt plaintext")
+	}
+
+	token := make([]byte, 0, len(encodedHeader)+4+
+		base64.RawURLEncoding.EncodedLen(len(encryptedKey))+
+		base64.RawURLEncoding.EncodedLen(len(iv))+
+		base64.RawURLEncoding.EncodedLen(len(ciphertext))+
+		base64.RawURLEncoding.EncodedLen(len(tag)))
+
+	token = append(token, encodedHeader...)
+	for _, segment := range [][]byte{encryptedKey, iv, ciphertext, tag} {
+		token = append(token, '.')
+		token = append(token, encodeSegment(segment)...)
+	}
+
+	return &DecodedParts{
+		raw:       token,
+		header:    h,
+		plaintext: raw,
+	}, nil
+}
+
+// encodeSegment returns base64url encoded data.
+func encodeSegment(data []byte) []byte {
+	encoded := make([]byte, base64.RawURLEncoding.EncodedLen(len(data)))
+	base64.RawURLEncoding.Encode(encoded, data)
+
+	return encoded
+}
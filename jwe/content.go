@@ -0,0 +1,170 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+var errInvalidPadding = errors.New("invalid padding")
+
+// contentCipher implements authenticated content encryption.
+type contentCipher struct {
+	// keySize is length of content encryption key in bytes.
+	keySize int
+	encrypt func(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error)
+	decrypt func(cek, iv, ciphertext, tag, aad []byte) ([]byte, error)
+}
+
+func contentCipherFor(enc ContentEncryption) (*contentCipher, error) {
+	switch enc {
+	case A128GCM:
+		return &contentCipher{16, encryptGCM, decryptGCM}, nil
+	case A256GCM:
+		return &contentCipher{32, encryptGCM, decryptGCM}, nil
+	case A128CBCHS256:
+		return &contentCipher{32, encryptCBCHS256, decryptCBCHS256}, nil
+	default:
+		return nil, ErrUnsupportedEncryption
+	}
+}
+
+// @see https://tools.ietf.org/html/rfc7518#section-5.3
+func encryptGCM(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	aead, err := newGCM(cek)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	iv = make([]byte, aead.NonceSize())
+
+	_, err = rand.Read(iv)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	sealed := aead.Seal(nil, iv, plaintext, aad)
+	split := len(sealed) - aead.Overhead()
+
+	return iv, sealed[:split], sealed[split:], nil
+}
+
+func decryptGCM(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	aead, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iv) != aead.NonceSize() || len(tag) != aead.Overhead() {
+		return nil, ErrDecryptionFailed
+	}
+
+	sealed := make([]byte, 0, len(ciphertext)+len(tag))
+	sealed = append(sealed, ciphertext...)
+	sealed = append(sealed, tag...)
+
+	return aead.Open(nil, iv, sealed, aad)
+}
+
+func newGCM(cek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// @see https://tools.ietf.org/html/rfc7518#section-5.2.2
+func encryptCBCHS256(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	macKey, encKey := cek[:16], cek[16:]
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	iv = make([]byte, aes.BlockSize)
+
+	_, err = rand.Read(iv)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ciphertext = pad(plaintext, aes.BlockSize)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return iv, ciphertext, cbcTag(macKey, aad, iv, ciphertext), nil
+}
+
+func decryptCBCHS256(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	macKey, encKey := cek[:16], cek[16:]
+
+	expected := cbcTag(macKey, aad, iv, ciphertext)
+	if subtle.ConstantTimeCompare(expected, tag) != 1 {
+		return nil, ErrDecryptionFailed
+	}
+
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrDecryptionFailed
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	return unpad(plaintext, aes.BlockSize)
+}
+
+// cbcTag computes authentication tag as HMAC SHA-256 of AAD || IV || ciphertext || AL, truncated to 16 bytes.
+func cbcTag(macKey, aad, iv, ciphertext []byte) []byte {
+	al := make([]byte, 8)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*8)
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(al)
+
+	return mac.Sum(nil)[:16]
+}
+
+// pad applies PKCS #7 padding.
+func pad(data []byte, blockSize int) []byte {
+	n := blockSize - len(data)%blockSize
+
+	padded := make([]byte, len(data)+n)
+	copy(padded, data)
+
+	for i := len(data); i < len(padded); i++ {
+		padded[i] = byte(n)
+	}
+
+	return padded
+}
+
+// unpad removes PKCS #7 padding.
+func unpad(data []byte, blockSize int) ([]byte, error) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > blockSize || n > len(data) {
+		return nil, errInvalidPadding
+	}
+
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, errInvalidPadding
+		}
+	}
+
+	return data[:len(data)-n], nil
+}
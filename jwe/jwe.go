@@ -0,0 +1,210 @@
+// Package jwe implements JSON Web Encryption in compact serialization.
+// @see https://tools.ietf.org/html/rfc7516
+package jwe
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/jwk"
+)
+
+// Block represents JWE errors.
+var (
+	ErrPartMissed              = errors.New("one of token parts missed")
+	ErrDecryptionFailed        = errors.New("failed to decrypt token")
+	ErrUnsupportedAlgorithm    = errors.New("unsupported key management algorithm")
+	ErrUnsupportedEncryption   = errors.New("unsupported content encryption algorithm")
+	ErrUnsupportedHeader       = errors.New("unsupported header parameter")
+	ErrEncryptionMismatch      = errors.New("token content encryption does not match the expected one")
+	ErrInvalidEphemeralKey     = errors.New("invalid ephemeral public key")
+	ErrUnexpectedEncryptedKey  = errors.New("encrypted key must be empty for the algorithm")
+	ErrInvalidContentKeyLength = errors.New("content encryption key has invalid length")
+)
+
+// KeyAlgorithm describes key management algorithms used to determine content encryption key.
+// @see https://tools.ietf.org/html/rfc7518#section-4.1
+type KeyAlgorithm string
+
+// KeyAlgorithm constants represents available key management algorithms values.
+const (
+	RSAOAEP      KeyAlgorithm = "RSA-OAEP"
+	RSAOAEP256   KeyAlgorithm = "RSA-OAEP-256"
+	A128KW       KeyAlgorithm = "A128KW"
+	A256KW       KeyAlgorithm = "A256KW"
+	Direct       KeyAlgorithm = "dir"
+	ECDHES       KeyAlgorithm = "ECDH-ES"
+	ECDHESA128KW KeyAlgorithm = "ECDH-ES+A128KW"
+	ECDHESA256KW KeyAlgorithm = "ECDH-ES+A256KW"
+)
+
+// ContentEncryption describes content encryption algorithms.
+// @see https://tools.ietf.org/html/rfc7518#section-5.1
+type ContentEncryption string
+
+// ContentEncryption constants represents available content encryption algorithms values.
+const (
+	A128GCM      ContentEncryption = "A128GCM"
+	A256GCM      ContentEncryption = "A256GCM"
+	A128CBCHS256 ContentEncryption = "A128CBC-HS256"
+)
+
+// Header stores JWE protected header data.
+type Header struct {
+	// The key management algorithm used
+	// @see https://tools.ietf.org/html/rfc7516#section-4.1.1
+	Alg KeyAlgorithm `json:"alg"`
+
+	// The content encryption algorithm used
+	// @see https://tools.ietf.org/html/rfc7516#section-4.1.2
+	Enc ContentEncryption `json:"enc"`
+
+	// Compression algorithm, not supported
+	// @see https://tools.ietf.org/html/rfc7516#section-4.1.3
+	Zip string `json:"zip,omitempty"`
+
+	// Type of the complete JWE
+	// @see https://tools.ietf.org/html/rfc7516#section-4.1.11
+	Typ string `json:"typ,omitempty"`
+
+	// Content type of the plaintext, "JWT" for nested tokens
+	// @see https://tools.ietf.org/html/rfc7516#section-4.1.12
+	Cty string `json:"cty,omitempty"`
+
+	// Key ID
+	// @see https://tools.ietf.org/html/rfc7516#section-4.1.6
+	Kid string `json:"kid,omitempty"`
+
+	// Ephemeral public key of ECDH-ES
+	// @see https://tools.ietf.org/html/rfc7518#section-4.6.1.1
+	Epk *jwk.Key `json:"epk,omitempty"`
+
+	// Agreement PartyUInfo of ECDH-ES
+	// @see https://tools.ietf.org/html/rfc7518#section-4.6.1.2
+	Apu string `json:"apu,omitempty"`
+
+	// Agreement PartyVInfo of ECDH-ES
+	// @see https://tools.ietf.org/html/rfc7518#section-4.6.1.3
+	Apv string `json:"apv,omitempty"`
+
+	// Critical header parameters, not supported
+	// @see https://tools.ietf.org/html/rfc7516#section-4.1.13
+	Crit []string `json:"crit,omitempty"`
+}
+
+// DecodedParts stores decrypted JWE.
+type DecodedParts struct {
+	raw       []byte
+	header    Header
+	plaintext json.RawMessage
+}
+
+// Header returns token's protected Header.
+func (t *DecodedParts) Header() Header {
+	return t.header
+}
+
+// RawClaims returns bytes with decrypted plaintext.
+func (t *DecodedParts) RawClaims() []byte {
+	return t.plaintext
+}
+
+// MarshalBinary inherited from encoding.BinaryMarshaler.
+func (t *DecodedParts) MarshalBinary() (data []byte, err error) {
+	return t.raw, nil
+}
+
+// MarshalText inherited from encoding.TextMarshaler.
+func (t *DecodedParts) MarshalText() (text []byte, err error) {
+	return t.MarshalBinary()
+}
+
+// Parser used to parse and decrypt compact JWE.
+// Token's alg and enc headers are pinned to the expected algorithms.
+type Parser struct {
+	alg KeyAlgorithm
+	enc ContentEncryption
+	key jwtee.Key
+}
+
+// NewParser returns new instance of Parser.
+// Key is recipient private key, shared symmetric key or content encryption key for "dir".
+func NewParser(alg KeyAlgorithm, enc ContentEncryption, key jwtee.Key) *Parser {
+	return &Parser{alg, enc, key}
+}
+
+// Parse splits and decrypts compact JWE.
+// Any cryptographic failure is reported as ErrDecryptionFailed.
+// Returned errors are *jwtee.TokenError wrapping the cause, so use errors.Is to check them.
+func (p *Parser) Parse(token json.RawMessage) (*DecodedParts, error) {
+	segments := bytes.Split(token, []byte{'.'})
+	if len(segments) != 5 {
+		return nil, newTokenError(jwtee.KindMalformed, jwtee.SegmentToken, ErrPartMissed)
+	}
+
+	decoded := make([][]byte, 5)
+
+	for i, segment := range segments {
+		d := make([]byte, base64.RawURLEncoding.DecodedLen(len(segment)))
+
+		n, err := base64.RawURLEncoding.Decode(d, segment)
+		if err != nil {
+			seg := jwtee.SegmentToken
+			if i == 0 {
+				seg = jwtee.SegmentHeader
+			}
+
+			return nil, newTokenError(jwtee.KindMalformed, seg, err)
+		}
+
+		decoded[i] = d[:n]
+	}
+
+	var h Header
+
+	err := json.Unmarshal(decoded[0], &h)
+	if err != nil {
+		return nil, newTokenError(jwtee.KindMalformed, jwtee.SegmentHeader, err)
+	}
+
+	if h.Alg != p.alg {
+		return nil, newTokenError(jwtee.KindUnverifiable, jwtee.SegmentHeader, jwtee.ErrAlgorithmMismatch)
+	}
+
+	if h.Enc != p.enc {
+		return nil, newTokenError(jwtee.KindUnverifiable, jwtee.SegmentHeader, ErrEncryptionMismatch)
+	}
+
+	if h.Zip != "" || len(h.Crit) > 0 {
+		return nil, newTokenError(jwtee.KindUnverifiable, jwtee.SegmentHeader, ErrUnsupportedHeader)
+	}
+
+	cipher, err := contentCipherFor(h.Enc)
+	if err != nil {
+		return nil, newTokenError(jwtee.KindUnverifiable, jwtee.SegmentHeader, err)
+	}
+
+	cek, err := decryptKey(h, cipher.keySize, decoded[1], p.key)
+	if err != nil {
+		return nil, newTokenError(jwtee.KindUnverifiable, jwtee.SegmentToken, err)
+	}
+
+	plaintext, err := cipher.decrypt(cek, decoded[2], decoded[3], decoded[4], segments[0])
+	if err != nil {
+		return nil, newTokenError(jwtee.KindUnverifiable, jwtee.SegmentToken, ErrDecryptionFailed)
+	}
+
+	return &DecodedParts{
+		raw:       token,
+		header:    h,
+		plaintext: plaintext,
+	}, nil
+}
+
+// newTokenError wraps err into jwtee.TokenError.
+func newTokenError(kind jwtee.ErrorKind, segment jwtee.Segment, err error) error {
+	return &jwtee.TokenError{Kind: kind, Segment: segment, Err: err}
+}
@@ -0,0 +1,242 @@
+package jwe_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/jwe"
+	"github.com/stretchr/testify/assert"
+)
+
+type rawPlaintext []byte
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (p rawPlaintext) MarshalBinary() (data []byte, err error) {
+	return p, nil
+}
+
+func TestParser_Parse(t *testing.T) {
+	// Example from https://tools.ietf.org/html/rfc7516#appendix-A.3
+	kek, err := base64.RawURLEncoding.DecodeString(`GawgguFyGrWKav7AX4VKUg`)
+	if err != nil {
+		t.Fatalf("failed to decode key: %v", err)
+	}
+
+	token := []byte(`eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0.6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ.AxY8DCtDaGlsbGljb3RoZQ.KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY.U0m_YmjN04DJvceFICbCVQ`)
+
+	tests := []struct {
+		desc    string
+		token   []byte
+		parser  *jwe.Parser
+		checker func(t *testing.T, parts *jwe.DecodedParts, err error)
+	}{
+		{
+			desc:   "successful decrypting",
+			token:  token,
+			parser: jwe.NewParser(jwe.A128KW, jwe.A128CBCHS256, jwtee.NewSharedSecretKey(kek)),
+			checker: func(t *testing.T, parts *jwe.DecodedParts, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, jwe.Header{Alg: jwe.A128KW, Enc: jwe.A128CBCHS256}, parts.Header())
+				assert.Equal(t, []byte(`Live long and prosper.`), parts.RawClaims())
+			},
+		},
+		{
+			desc:   "failed decrypting with another key",
+			token:  token,
+			parser: jwe.NewParser(jwe.A128KW, jwe.A128CBCHS256, jwtee.NewSharedSecretKey(make([]byte, 16))),
+			checker: func(t *testing.T, parts *jwe.DecodedParts, err error) {
+				assert.True(t, errors.Is(err, jwe.ErrDecryptionFailed))
+			},
+		},
+		{
+			desc:   "failed decrypting modified ciphertext",
+			token:  bytes.Replace(token, []byte(`KDlTtXchhZ`), []byte(`KDlTtXchhA`), 1),
+			parser: jwe.NewParser(jwe.A128KW, jwe.A128CBCHS256, jwtee.NewSharedSecretKey(kek)),
+			checker: func(t *testing.T, parts *jwe.DecodedParts, err error) {
+				assert.True(t, errors.Is(err, jwe.ErrDecryptionFailed))
+			},
+		},
+		{
+			desc:   "failed decrypting with unexpected algorithm",
+			token:  token,
+			parser: jwe.NewParser(jwe.Direct, jwe.A128CBCHS256, jwtee.NewSharedSecretKey(kek)),
+			checker: func(t *testing.T, parts *jwe.DecodedParts, err error) {
+				assert.True(t, errors.Is(err, jwtee.ErrAlgorithmMismatch))
+				assert.True(t, errors.Is(err, jwtee.ErrKindUnverifiable))
+			},
+		},
+		{
+			desc:   "failed decrypting with unexpected encryption",
+			token:  token,
+			parser: jwe.NewParser(jwe.A128KW, jwe.A128GCM, jwtee.NewSharedSecretKey(kek)),
+			checker: func(t *testing.T, parts *jwe.DecodedParts, err error) {
+				assert.True(t, errors.Is(err, jwe.ErrEncryptionMismatch))
+			},
+		},
+		{
+			desc:   "failed decrypting token with missed part",
+			token:  token[:bytes.LastIndexByte(token, '.')],
+			parser: jwe.NewParser(jwe.A128KW, jwe.A128CBCHS256, jwtee.NewSharedSecretKey(kek)),
+			checker: func(t *testing.T, parts *jwe.DecodedParts, err error) {
+				assert.True(t, errors.Is(err, jwe.ErrPartMissed))
+				assert.True(t, errors.Is(err, jwtee.ErrKindMalformed))
+			},
+		},
+		{
+			desc:   "failed decrypting token with invalid base64url header",
+			token:  append([]byte(`!`), token...),
+			parser: jwe.NewParser(jwe.A128KW, jwe.A128CBCHS256, jwtee.NewSharedSecretKey(kek)),
+			checker: func(t *testing.T, parts *jwe.DecodedParts, err error) {
+				assert.True(t, errors.Is(err, &jwtee.TokenError{Kind: jwtee.KindMalformed, Segment: jwtee.SegmentHeader}))
+			},
+		},
+		{
+			desc:   "failed decrypting token with invalid header",
+			token:  append([]byte(`W10`), token[bytes.IndexByte(token, '.'):]...),
+			parser: jwe.NewParser(jwe.A128KW, jwe.A128CBCHS256, jwtee.NewSharedSecretKey(kek)),
+			checker: func(t *testing.T, parts *jwe.DecodedParts, err error) {
+				assert.True(t, errors.Is(err, jwtee.ErrKindMalformed))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			parts, err := test.parser.Parse(test.token)
+			test.checker(t, parts, err)
+		})
+	}
+}
+
+func TestBuilder_Build(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+
+	secret := func(size int) jwtee.Key {
+		return jwtee.NewSharedSecretKey(bytes.Repeat([]byte{7}, size))
+	}
+
+	tests := []struct {
+		desc       string
+		alg        jwe.KeyAlgorithm
+		encryptKey jwtee.Key
+		decryptKey jwtee.Key
+	}{
+		{
+			desc:       "RSA-OAEP",
+			alg:        jwe.RSAOAEP,
+			encryptKey: jwtee.NewRSAPublicKey(&rsaKey.PublicKey),
+			decryptKey: jwtee.NewRSAPrivateKey(rsaKey),
+		},
+		{
+			desc:       "RSA-OAEP-256",
+			alg:        jwe.RSAOAEP256,
+			encryptKey: jwtee.NewRSAPublicKey(&rsaKey.PublicKey),
+			decryptKey: jwtee.NewRSAPrivateKey(rsaKey),
+		},
+		{
+			desc:       "A128KW",
+			alg:        jwe.A128KW,
+			encryptKey: secret(16),
+			decryptKey: secret(16),
+		},
+		{
+			desc:       "A256KW",
+			alg:        jwe.A256KW,
+			encryptKey: secret(32),
+			decryptKey: secret(32),
+		},
+		{
+			desc:       "ECDH-ES",
+			alg:        jwe.ECDHES,
+			encryptKey: jwtee.NewECDSAPublicKey(&ecKey.PublicKey),
+			decryptKey: jwtee.NewECDSAPrivateKey(ecKey),
+		},
+		{
+			desc:       "ECDH-ES+A128KW",
+			alg:        jwe.ECDHESA128KW,
+			encryptKey: jwtee.NewECDSAPublicKey(&ecKey.PublicKey),
+			decryptKey: jwtee.NewECDSAPrivateKey(ecKey),
+		},
+		{
+			desc:       "ECDH-ES+A256KW",
+			alg:        jwe.ECDHESA256KW,
+			encryptKey: jwtee.NewECDSAPublicKey(&ecKey.PublicKey),
+			decryptKey: jwtee.NewECDSAPrivateKey(ecKey),
+		},
+	}
+
+	encryptions := []jwe.ContentEncryption{jwe.A128GCM, jwe.A256GCM, jwe.A128CBCHS256}
+	plaintext := rawPlaintext(`{"sub":"1234567890","name":"John Doe"}`)
+
+	for _, test := range tests {
+		for _, enc := range encryptions {
+			t.Run(test.desc+" "+string(enc), func(t *testing.T) {
+				built, err := jwe.NewBuilder(test.alg, enc).WithKID("k1").Build(plaintext, test.encryptKey)
+				assert.NoError(t, err)
+
+				token, err := built.MarshalText()
+				assert.NoError(t, err)
+
+				parts, err := jwe.NewParser(test.alg, enc, test.decryptKey).Parse(token)
+				assert.NoError(t, err)
+				assert.Equal(t, []byte(plaintext), parts.RawClaims())
+				assert.Equal(t, "k1", parts.Header().Kid)
+			})
+		}
+	}
+}
+
+func TestBuilder_BuildDirect(t *testing.T) {
+	cek := jwtee.NewSharedSecretKey(bytes.Repeat([]byte{1}, 32))
+	plaintext := rawPlaintext(`{"sub":"1234567890"}`)
+
+	built, err := jwe.NewBuilder(jwe.Direct, jwe.A256GCM).Build(plaintext, cek)
+	assert.NoError(t, err)
+
+	token, err := built.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, bytes.Count(token, []byte{'.'}))
+
+	parts, err := jwe.NewParser(jwe.Direct, jwe.A256GCM, cek).Parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(plaintext), parts.RawClaims())
+
+	_, err = jwe.NewBuilder(jwe.Direct, jwe.A128GCM).Build(plaintext, cek)
+	assert.Error(t, err)
+}
+
+func TestParser_ParseWithInvalidEphemeralKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+
+	otherCurve, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+
+	built, err := jwe.NewBuilder(jwe.ECDHES, jwe.A128GCM).Build(rawPlaintext(`{}`), jwtee.NewECDSAPublicKey(&otherCurve.PublicKey))
+	assert.NoError(t, err)
+
+	token, err := built.MarshalText()
+	assert.NoError(t, err)
+
+	_, err = jwe.NewParser(jwe.ECDHES, jwe.A128GCM, jwtee.NewECDSAPrivateKey(ecKey)).Parse(token)
+	assert.True(t, errors.Is(err, jwe.ErrInvalidEphemeralKey))
+}
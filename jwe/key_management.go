@@ -0,0 +1,305 @@
+package jwe
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1" // link binary
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/jwk"
+)
+
+// encryptKey determines content encryption key and its encrypted form for the recipient key.
+// Algorithm specific header parameters (e.g. epk) are set to h.
+// @see https://tools.ietf.org/html/rfc7516#section-5.1
+func encryptKey(h *Header, cekSize int, key jwtee.Key) (cek, encryptedKey []byte, err error) {
+	switch h.Alg {
+	case RSAOAEP, RSAOAEP256:
+		public, ok := key.PublicKey().(*rsa.PublicKey)
+		if !ok {
+			return nil, nil, jwtee.ErrInvalidKey
+		}
+
+		cek, err = randomKey(cekSize)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		encryptedKey, err = rsa.EncryptOAEP(oaepHash(h.Alg).New(), rand.Reader, public, cek, nil)
+
+		return cek, encryptedKey, err
+	case A128KW, A256KW:
+		kek, err := wrappingKey(h.Alg, key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cek, err = randomKey(cekSize)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		encryptedKey, err = wrapKey(kek, cek)
+
+		return cek, encryptedKey, err
+	case Direct:
+		if len(key.Secret()) != cekSize {
+			return nil, nil, ErrInvalidContentKeyLength
+		}
+
+		return key.Secret(), nil, nil
+	case ECDHES, ECDHESA128KW, ECDHESA256KW:
+		return encryptECDHKey(h, cekSize, key)
+	default:
+		return nil, nil, ErrUnsupportedAlgorithm
+	}
+}
+
+// decryptKey determines content encryption key from its encrypted form with the recipient key.
+// @see https://tools.ietf.org/html/rfc7516#section-5.2
+func decryptKey(h Header, cekSize int, encryptedKey []byte, key jwtee.Key) ([]byte, error) {
+	switch h.Alg {
+	case RSAOAEP, RSAOAEP256:
+		decrypter, ok := key.PrivateKey().(crypto.Decrypter)
+		if !ok {
+			return nil, jwtee.ErrInvalidKey
+		}
+
+		if _, ok = decrypter.Public().(*rsa.PublicKey); !ok {
+			return nil, jwtee.ErrInvalidKey
+		}
+
+		cek, err := decrypter.Decrypt(rand.Reader, encryptedKey, &rsa.OAEPOptions{Hash: oaepHash(h.Alg)})
+		if err != nil || len(cek) != cekSize {
+			// Proceed with random key to not reveal whether key decryption failed.
+			// @see https://tools.ietf.org/html/rfc7516#section-11.5
+			return randomKey(cekSize)
+		}
+
+		return cek, nil
+	case A128KW, A256KW:
+		kek, err := wrappingKey(h.Alg, key)
+		if err != nil {
+			return nil, err
+		}
+
+		return unwrapContentKey(kek, encryptedKey, cekSize)
+	case Direct:
+		if len(encryptedKey) != 0 {
+			return nil, ErrUnexpectedEncryptedKey
+		}
+
+		if len(key.Secret()) != cekSize {
+			return nil, ErrInvalidContentKeyLength
+		}
+
+		return key.Secret(), nil
+	case ECDHES, ECDHESA128KW, ECDHESA256KW:
+		return decryptECDHKey(h, cekSize, encryptedKey, key)
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+func encryptECDHKey(h *Header, cekSize int, key jwtee.Key) (cek, encryptedKey []byte, err error) {
+	public, ok := key.PublicKey().(*ecdsa.PublicKey)
+	if !ok {
+		return nil, nil, jwtee.ErrInvalidKey
+	}
+
+	ephemeral, err := ecdsa.GenerateKey(public.Curve, rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h.Epk, err = jwk.FromKey(jwtee.NewECDSAPublicKey(&ephemeral.PublicKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	z, err := sharedSecret(ephemeral, public)
+	if err != nil {
+		return nil, nil, jwtee.ErrInvalidKey
+	}
+
+	if h.Alg == ECDHES {
+		cek, err = deriveKey(*h, z, cekSize)
+
+		return cek, nil, err
+	}
+
+	kek, err := deriveKey(*h, z, keyWrapSize(h.Alg))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cek, err = randomKey(cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err = wrapKey(kek, cek)
+
+	return cek, encryptedKey, err
+}
+
+func decryptECDHKey(h Header, cekSize int, encryptedKey []byte, key jwtee.Key) ([]byte, error) {
+	private, ok := key.PrivateKey().(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	if h.Epk == nil || h.Epk.Kty != jwtee.KeyTypeEC || h.Epk.IsPrivate() {
+		return nil, ErrInvalidEphemeralKey
+	}
+
+	epk, err := h.Epk.JWTKey()
+	if err != nil {
+		return nil, ErrInvalidEphemeralKey
+	}
+
+	ephemeral, ok := epk.PublicKey().(*ecdsa.PublicKey)
+	if !ok || ephemeral.Curve != private.Curve {
+		return nil, ErrInvalidEphemeralKey
+	}
+
+	z, err := sharedSecret(private, ephemeral)
+	if err != nil {
+		return nil, ErrInvalidEphemeralKey
+	}
+
+	if h.Alg == ECDHES {
+		if len(encryptedKey) != 0 {
+			return nil, ErrUnexpectedEncryptedKey
+		}
+
+		return deriveKey(h, z, cekSize)
+	}
+
+	kek, err := deriveKey(h, z, keyWrapSize(h.Alg))
+	if err != nil {
+		return nil, err
+	}
+
+	return unwrapContentKey(kek, encryptedKey, cekSize)
+}
+
+// sharedSecret returns x coordinate of ECDH shared point, padded to the curve size.
+// Public key is checked to be a valid point of the private key curve.
+// @see https://tools.ietf.org/html/rfc7518#section-4.6.2
+func sharedSecret(private *ecdsa.PrivateKey, public *ecdsa.PublicKey) ([]byte, error) {
+	priv, err := private.ECDH()
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := public.ECDH()
+	if err != nil {
+		return nil, err
+	}
+
+	return priv.ECDH(pub)
+}
+
+// deriveKey derives key of given size from shared secret with Concat KDF using SHA-256.
+// @see https://tools.ietf.org/html/rfc7518#section-4.6.2
+func deriveKey(h Header, z []byte, size int) ([]byte, error) {
+	algID := string(h.Alg)
+	if h.Alg == ECDHES {
+		algID = string(h.Enc)
+	}
+
+	apu, err := base64.RawURLEncoding.DecodeString(h.Apu)
+	if err != nil {
+		return nil, ErrUnsupportedHeader
+	}
+
+	apv, err := base64.RawURLEncoding.DecodeString(h.Apv)
+	if err != nil {
+		return nil, ErrUnsupportedHeader
+	}
+
+	otherInfo := make([]byte, 0, 4+len(algID)+4+len(apu)+4+len(apv)+4)
+	otherInfo = appendLengthPrefixed(otherInfo, []byte(algID))
+	otherInfo = appendLengthPrefixed(otherInfo, apu)
+	otherInfo = appendLengthPrefixed(otherInfo, apv)
+	otherInfo = appendUint32(otherInfo, uint32(size*8))
+
+	derived := make([]byte, 0, size+sha256.Size)
+	digest := sha256.New()
+
+	for counter := uint32(1); len(derived) < size; counter++ {
+		digest.Reset()
+		digest.Write(appendUint32(nil, counter))
+		digest.Write(z)
+		digest.Write(otherInfo)
+		derived = digest.Sum(derived)
+	}
+
+	return derived[:size], nil
+}
+
+func appendLengthPrefixed(dst, data []byte) []byte {
+	return append(appendUint32(dst, uint32(len(data))), data...)
+}
+
+func appendUint32(dst []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+
+	return append(dst, buf[:]...)
+}
+
+func unwrapContentKey(kek, encryptedKey []byte, cekSize int) ([]byte, error) {
+	cek, err := unwrapKey(kek, encryptedKey)
+	if err != nil || len(cek) != cekSize {
+		return nil, ErrDecryptionFailed
+	}
+
+	return cek, nil
+}
+
+func wrappingKey(alg KeyAlgorithm, key jwtee.Key) ([]byte, error) {
+	kek := key.Secret()
+	if len(kek) != keyWrapSize(alg) {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	return kek, nil
+}
+
+// keyWrapSize returns size of AES Key Wrap key in bytes.
+func keyWrapSize(alg KeyAlgorithm) int {
+	switch alg {
+	case A128KW, ECDHESA128KW:
+		return 16
+	case A256KW, ECDHESA256KW:
+		return 32
+	default:
+		return 0
+	}
+}
+
+func oaepHash(alg KeyAlgorithm) crypto.Hash {
+	if alg == RSAOAEP256 {
+		return crypto.SHA256
+	}
+
+	return crypto.SHA1
+}
+
+func randomKey(size int) ([]byte, error) {
+	key := make([]byte, size)
+
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,52 @@
+package jwe
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedSecret(t *testing.T) {
+	tests := []struct {
+		desc  string
+		curve elliptic.Curve
+		size  int
+	}{
+		{"P-256", elliptic.P256(), 32},
+		{"P-384", elliptic.P384(), 48},
+		{"P-521", elliptic.P521(), 66},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			alice, err := ecdsa.GenerateKey(test.curve, rand.Reader)
+			assert.NoError(t, err)
+
+			bob, err := ecdsa.GenerateKey(test.curve, rand.Reader)
+			assert.NoError(t, err)
+
+			z1, err := sharedSecret(alice, &bob.PublicKey)
+			assert.NoError(t, err)
+			assert.Len(t, z1, test.size)
+
+			z2, err := sharedSecret(bob, &alice.PublicKey)
+			assert.NoError(t, err)
+			assert.Equal(t, z1, z2)
+
+			offCurve := &ecdsa.PublicKey{
+				Curve: test.curve,
+				X:     bob.X,
+				Y:     new(big.Int).Add(bob.Y, big.NewInt(1)),
+			}
+
+			_, err = sharedSecret(alice, offCurve)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package jwe
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/furdarius/jwtee"
+)
+
+// Block represents nested JWT errors.
+var (
+	ErrNotNested = errors.New("token is not nested JWT")
+)
+
+// nestedContentType is the cty value of nested JWT.
+// @see https://tools.ietf.org/html/rfc7519#section-5.2
+const nestedContentType = "JWT"
+
+// NestedParts stores decrypted and verified layers of nested JWT.
+// @see https://tools.ietf.org/html/rfc7519#section-5.2
+type NestedParts struct {
+	outer *DecodedParts
+	inner *jwtee.DecodedParts
+}
+
+// Header returns header of the outer (encryption) layer.
+func (t *NestedParts) Header() Header {
+	return t.outer.Header()
+}
+
+// Inner returns parts of the inner (signed) token.
+func (t *NestedParts) Inner() *jwtee.DecodedParts {
+	return t.inner
+}
+
+// RawClaims returns bytes with decoded claims of the inner token.
+func (t *NestedParts) RawClaims() []byte {
+	return t.inner.RawClaims()
+}
+
+// MarshalBinary inherited from encoding.BinaryMarshaler.
+func (t *NestedParts) MarshalBinary() (data []byte, err error) {
+	return t.outer.MarshalBinary()
+}
+
+// MarshalText inherited from encoding.TextMarshaler.
+func (t *NestedParts) MarshalText() (text []byte, err error) {
+	return t.MarshalBinary()
+}
+
+// NestedBuilder used to build nested JWT: claims are signed, then the signed token is encrypted.
+type NestedBuilder struct {
+	signing    jwtee.Builder
+	encrypting *Builder
+}
+
+// NewNestedBuilder returns new instance of NestedBuilder.
+// The cty header of the encryption layer is always set to "JWT".
+func NewNestedBuilder(signing jwtee.Builder, encrypting *Builder) *NestedBuilder {
+	return &NestedBuilder{signing, encrypting}
+}
+
+// Build used to sign claims with signingKey and encrypt the result for encryptionKey.
+func (b *NestedBuilder) Build(claims encoding.BinaryMarshaler, signer jwtee.Signer, signingKey, encryptionKey jwtee.Key) (*NestedParts, error) {
+	inner, err := b.signing.Build(claims, signer, signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypting := *b.encrypting
+	encrypting.h.Cty = nestedContentType
+
+	outer, err := encrypting.Build(inner, encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NestedParts{outer, inner}, nil
+}
+
+// NestedParser used to decrypt nested JWT and then parse the inner token.
+type NestedParser struct {
+	decrypting *Parser
+	inner      *jwtee.VerifyingParser
+}
+
+// NewNestedParser returns new instance of NestedParser.
+// Inner parser is jwtee.VerifyingParser, so the inner token signature is always verified.
+func NewNestedParser(decrypting *Parser, inner *jwtee.VerifyingParser) *NestedParser {
+	return &NestedParser{decrypting, inner}
+}
+
+// Parse decrypts token and parses the inner one.
+// If encryption layer has no "cty":"JWT" header then ErrNotNested returns.
+func (p *NestedParser) Parse(token json.RawMessage) (*NestedParts, error) {
+	outer, err := p.decrypting.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(outer.Header().Cty, nestedContentType) {
+		return nil, ErrNotNested
+	}
+
+	inner, err := p.inner.Parse(outer.RawClaims())
+	if err != nil {
+		return nil, err
+	}
+
+	return &NestedParts{outer, inner}, nil
+}
@@ -0,0 +1,105 @@
+package jwe_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/jwe"
+	"github.com/furdarius/jwtee/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+type testclaims struct {
+	jwtee.RegisteredClaims
+
+	Name string `json:"name"`
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c testclaims) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(c)
+}
+
+func TestNestedParser_Parse(t *testing.T) {
+	recipient, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	signingKey := jwtee.NewSharedSecretKey([]byte(`secret`))
+	encryptionKey := jwtee.NewRSAPublicKey(&recipient.PublicKey)
+	decryptionKey := jwtee.NewRSAPrivateKey(recipient)
+
+	builder := jwe.NewNestedBuilder(jwtee.NewTokenBuilder(), jwe.NewBuilder(jwe.RSAOAEP256, jwe.A256GCM))
+
+	nested, err := builder.Build(testclaims{Name: "John Doe"}, signer.NewHS256(), signingKey, encryptionKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "JWT", nested.Header().Cty)
+
+	token, err := nested.MarshalText()
+	assert.NoError(t, err)
+
+	notNested, err := jwe.NewBuilder(jwe.RSAOAEP256, jwe.A256GCM).Build(testclaims{Name: "John Doe"}, encryptionKey)
+	assert.NoError(t, err)
+
+	notNestedToken, err := notNested.MarshalText()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		desc    string
+		token   []byte
+		parser  *jwe.NestedParser
+		checker func(t *testing.T, parts *jwe.NestedParts, err error)
+	}{
+		{
+			desc:  "successful parsing",
+			token: token,
+			parser: jwe.NewNestedParser(
+				jwe.NewParser(jwe.RSAOAEP256, jwe.A256GCM, decryptionKey),
+				jwtee.NewVerifyingParser(jwtee.NewJSONParser(), jwtee.NewPartsVerifier(signer.NewHS256(), signingKey)),
+			),
+			checker: func(t *testing.T, parts *jwe.NestedParts, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, jwe.Header{Alg: jwe.RSAOAEP256, Enc: jwe.A256GCM, Cty: "JWT"}, parts.Header())
+				assert.Equal(t, jwtee.HS256, parts.Inner().Header().Alg)
+
+				var claims testclaims
+				assert.NoError(t, json.Unmarshal(parts.RawClaims(), &claims))
+				assert.Equal(t, "John Doe", claims.Name)
+			},
+		},
+		{
+			desc:  "failed parsing with invalid inner signature",
+			token: token,
+			parser: jwe.NewNestedParser(
+				jwe.NewParser(jwe.RSAOAEP256, jwe.A256GCM, decryptionKey),
+				jwtee.NewVerifyingParser(jwtee.NewJSONParser(), jwtee.NewPartsVerifier(signer.NewHS256(), jwtee.NewSharedSecretKey([]byte(`other`)))),
+			),
+			checker: func(t *testing.T, parts *jwe.NestedParts, err error) {
+				assert.True(t, errors.Is(err, jwtee.ErrInvalidSignature))
+			},
+		},
+		{
+			desc:  "failed parsing not nested token",
+			token: notNestedToken,
+			parser: jwe.NewNestedParser(
+				jwe.NewParser(jwe.RSAOAEP256, jwe.A256GCM, decryptionKey),
+				jwtee.NewVerifyingParser(jwtee.NewJSONParser(), jwtee.NewPartsVerifier(signer.NewHS256(), signingKey)),
+			),
+			checker: func(t *testing.T, parts *jwe.NestedParts, err error) {
+				assert.Equal(t, jwe.ErrNotNested, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			parts, err := test.parser.Parse(test.token)
+			test.checker(t, parts, err)
+		})
+	}
+}
@@ -0,0 +1,450 @@
+// Package jwk implements JSON Web Key and JSON Web Key Set parsing and serialization.
+// @see https://tools.ietf.org/html/rfc7517
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/furdarius/jwtee"
+)
+
+// Block represents JWK errors.
+var (
+	ErrUnsupportedKeyType = errors.New("unsupported key type")
+	ErrUnsupportedCurve   = errors.New("unsupported curve")
+	ErrMissingParameter   = errors.New("required key parameter missing")
+	ErrInvalidParameter   = errors.New("key parameter is invalid")
+)
+
+// Key usage values of the "use" parameter.
+// @see https://tools.ietf.org/html/rfc7517#section-4.2
+const (
+	UseSignature  = "sig"
+	UseEncryption = "enc"
+)
+
+// Curve names of the "crv" parameter.
+// @see https://tools.ietf.org/html/rfc7518#section-6.2.1.1
+// @see https://tools.ietf.org/html/rfc8037#section-2
+const (
+	CurveP256    = "P-256"
+	CurveP384    = "P-384"
+	CurveP521    = "P-521"
+	CurveEd25519 = "Ed25519"
+)
+
+// Key represents JSON Web Key.
+// Binary parameters are stored base64url encoded, as they appear in JSON.
+type Key struct {
+	// Key Type
+	// @see https://tools.ietf.org/html/rfc7517#section-4.1
+	Kty jwtee.KeyType `json:"kty"`
+
+	// Public Key Use
+	// @see https://tools.ietf.org/html/rfc7517#section-4.2
+	Use string `json:"use,omitempty"`
+
+	// Key Operations
+	// @see https://tools.ietf.org/html/rfc7517#section-4.3
+	KeyOps []string `json:"key_ops,omitempty"`
+
+	// Algorithm
+	// @see https://tools.ietf.org/html/rfc7517#section-4.4
+	Alg jwtee.Algorithm `json:"alg,omitempty"`
+
+	// Key ID
+	// @see https://tools.ietf.org/html/rfc7517#section-4.5
+	Kid string `json:"kid,omitempty"`
+
+	// Curve of EC and OKP keys
+	// @see https://tools.ietf.org/html/rfc7518#section-6.2.1.1
+	Crv string `json:"crv,omitempty"`
+
+	// X coordinate of EC key or public key of OKP key
+	X string `json:"x,omitempty"`
+
+	// Y coordinate of EC key
+	Y string `json:"y,omitempty"`
+
+	// Modulus of RSA key
+	// @see https://tools.ietf.org/html/rfc7518#section-6.3.1.1
+	N string `json:"n,omitempty"`
+
+	// Exponent of RSA key
+	E string `json:"e,omitempty"`
+
+	// Private exponent of RSA key, private key of EC and OKP keys
+	D string `json:"d,omitempty"`
+
+	// Prime factors and CRT parameters of RSA key
+	// @see https://tools.ietf.org/html/rfc7518#section-6.3.2
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	Dp string `json:"dp,omitempty"`
+	Dq string `json:"dq,omitempty"`
+	Qi string `json:"qi,omitempty"`
+
+	// Key value of symmetric key
+	// @see https://tools.ietf.org/html/rfc7518#section-6.4.1
+	K string `json:"k,omitempty"`
+}
+
+// Parse decodes JSON Web Key from JSON.
+func Parse(data []byte) (*Key, error) {
+	var k Key
+
+	err := json.Unmarshal(data, &k)
+	if err != nil {
+		return nil, err
+	}
+
+	if k.Kty == "" {
+		return nil, ErrMissingParameter
+	}
+
+	return &k, nil
+}
+
+// FromKey returns JSON Web Key representing jwtee.Key.
+// Private parts are included if key has them, use Public to strip them.
+func FromKey(key jwtee.Key) (*Key, error) {
+	k := &Key{
+		Kty: key.Type(),
+		Alg: key.Algorithm(),
+		Kid: key.ID(),
+	}
+
+	switch public := key.PublicKey().(type) {
+	case *rsa.PublicKey:
+		k.N = encodeBigInt(public.N, 0)
+		k.E = encodeBigInt(big.NewInt(int64(public.E)), 0)
+
+		if private, ok := key.PrivateKey().(*rsa.PrivateKey); ok {
+			k.D = encodeBigInt(private.D, 0)
+
+			if len(private.Primes) == 2 {
+				private.Precompute()
+				k.P = encodeBigInt(private.Primes[0], 0)
+				k.Q = encodeBigInt(private.Primes[1], 0)
+				k.Dp = encodeBigInt(private.Precomputed.Dp, 0)
+				k.Dq = encodeBigInt(private.Precomputed.Dq, 0)
+				k.Qi = encodeBigInt(private.Precomputed.Qinv, 0)
+			}
+		}
+	case *ecdsa.PublicKey:
+		crv, size, err := curveName(public.Curve)
+		if err != nil {
+			return nil, err
+		}
+
+		k.Crv = crv
+		k.X = encodeBigInt(public.X, size)
+		k.Y = encodeBigInt(public.Y, size)
+
+		if private, ok := key.PrivateKey().(*ecdsa.PrivateKey); ok {
+			k.D = encodeBigInt(private.D, size)
+		}
+	case ed25519.PublicKey:
+		k.Crv = CurveEd25519
+		k.X = base64.RawURLEncoding.EncodeToString(public)
+
+		if private, ok := key.PrivateKey().(ed25519.PrivateKey); ok {
+			k.D = base64.RawURLEncoding.EncodeToString(private.Seed())
+		}
+	default:
+		if key.Type() != jwtee.KeyTypeOct || key.Secret() == nil {
+			return nil, ErrUnsupportedKeyType
+		}
+
+		k.K = base64.RawURLEncoding.EncodeToString(key.Secret())
+	}
+
+	return k, nil
+}
+
+// IsPrivate returns true if JWK holds private or symmetric key.
+func (k *Key) IsPrivate() bool {
+	return k.D != "" || k.K != ""
+}
+
+// Public returns copy of the JWK without private parameters.
+func (k *Key) Public() *Key {
+	public := *k
+	public.D, public.P, public.Q, public.Dp, public.Dq, public.Qi, public.K = "", "", "", "", "", "", ""
+
+	return &public
+}
+
+// Algorithm returns algorithm of the key, inferring it from curve if "alg" is absent.
+// Empty string returns if algorithm cannot be inferred.
+func (k *Key) Algorithm() jwtee.Algorithm {
+	if k.Alg != "" {
+		return k.Alg
+	}
+
+	switch k.Crv {
+	case CurveP256:
+		return jwtee.ES256
+	case CurveP384:
+		return jwtee.ES384
+	case CurveP521:
+		return jwtee.ES512
+	case CurveEd25519:
+		return jwtee.EdDSA
+	default:
+		return ""
+	}
+}
+
+// JWTKey converts JWK to jwtee.Key usable by signers.
+// Kid and alg parameters are carried to the returned key.
+func (k *Key) JWTKey() (jwtee.Key, error) {
+	var (
+		key jwtee.Key
+		err error
+	)
+
+	switch k.Kty {
+	case jwtee.KeyTypeOct:
+		key, err = k.octKey()
+	case jwtee.KeyTypeRSA:
+		key, err = k.rsaKey()
+	case jwtee.KeyTypeEC:
+		key, err = k.ecKey()
+	case jwtee.KeyTypeOKP:
+		key, err = k.okpKey()
+	default:
+		return jwtee.Key{}, ErrUnsupportedKeyType
+	}
+
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	return key.WithID(k.Kid).WithAlgorithm(k.Alg), nil
+}
+
+func (k *Key) octKey() (jwtee.Key, error) {
+	if k.K == "" {
+		return jwtee.Key{}, ErrMissingParameter
+	}
+
+	secret, err := base64.RawURLEncoding.DecodeString(k.K)
+	if err != nil {
+		return jwtee.Key{}, ErrInvalidParameter
+	}
+
+	return jwtee.NewSharedSecretKey(secret), nil
+}
+
+func (k *Key) rsaKey() (jwtee.Key, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	e, err := decodeBigInt(k.E)
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	if !e.IsInt64() || e.Int64() > 1<<31-1 || e.Int64() < 2 {
+		return jwtee.Key{}, ErrInvalidParameter
+	}
+
+	public := rsa.PublicKey{
+		N: n,
+		E: int(e.Int64()),
+	}
+
+	if k.D == "" {
+		return jwtee.NewRSAPublicKey(&public), nil
+	}
+
+	d, err := decodeBigInt(k.D)
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	private := &rsa.PrivateKey{
+		PublicKey: public,
+		D:         d,
+	}
+
+	if k.P == "" || k.Q == "" {
+		return jwtee.Key{}, ErrMissingParameter
+	}
+
+	p, err := decodeBigInt(k.P)
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	q, err := decodeBigInt(k.Q)
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	private.Primes = []*big.Int{p, q}
+
+	err = private.Validate()
+	if err != nil {
+		return jwtee.Key{}, ErrInvalidParameter
+	}
+
+	private.Precompute()
+
+	return jwtee.NewRSAPrivateKey(private), nil
+}
+
+func (k *Key) ecKey() (jwtee.Key, error) {
+	curve, size, err := curveByName(k.Crv)
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	x, err := decodeFixedBigInt(k.X, size)
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	y, err := decodeFixedBigInt(k.Y, size)
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	if !curve.IsOnCurve(x, y) {
+		return jwtee.Key{}, ErrInvalidParameter
+	}
+
+	public := ecdsa.PublicKey{
+		Curve: curve,
+		X:     x,
+		Y:     y,
+	}
+
+	if k.D == "" {
+		return jwtee.NewECDSAPublicKey(&public), nil
+	}
+
+	d, err := decodeFixedBigInt(k.D, size)
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	px, py := curve.ScalarBaseMult(d.Bytes())
+	if px.Cmp(x) != 0 || py.Cmp(y) != 0 {
+		return jwtee.Key{}, ErrInvalidParameter
+	}
+
+	return jwtee.NewECDSAPrivateKey(&ecdsa.PrivateKey{
+		PublicKey: public,
+		D:         d,
+	}), nil
+}
+
+func (k *Key) okpKey() (jwtee.Key, error) {
+	if k.Crv != CurveEd25519 {
+		return jwtee.Key{}, ErrUnsupportedCurve
+	}
+
+	x, err := decodeFixedBytes(k.X, ed25519.PublicKeySize)
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	if k.D == "" {
+		return jwtee.NewEd25519PublicKey(ed25519.PublicKey(x)), nil
+	}
+
+	seed, err := decodeFixedBytes(k.D, ed25519.SeedSize)
+	if err != nil {
+		return jwtee.Key{}, err
+	}
+
+	private := ed25519.NewKeyFromSeed(seed)
+	if !private.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(x)) {
+		return jwtee.Key{}, ErrInvalidParameter
+	}
+
+	return jwtee.NewEd25519PrivateKey(private), nil
+}
+
+func curveByName(name string) (curve elliptic.Curve, size int, err error) {
+	switch name {
+	case CurveP256:
+		return elliptic.P256(), 32, nil
+	case CurveP384:
+		return elliptic.P384(), 48, nil
+	case CurveP521:
+		return elliptic.P521(), 66, nil
+	default:
+		return nil, 0, ErrUnsupportedCurve
+	}
+}
+
+func curveName(curve elliptic.Curve) (name string, size int, err error) {
+	switch curve {
+	case elliptic.P256():
+		return CurveP256, 32, nil
+	case elliptic.P384():
+		return CurveP384, 48, nil
+	case elliptic.P521():
+		return CurveP521, 66, nil
+	default:
+		return "", 0, ErrUnsupportedCurve
+	}
+}
+
+func encodeBigInt(n *big.Int, size int) string {
+	if size == 0 {
+		return base64.RawURLEncoding.EncodeToString(n.Bytes())
+	}
+
+	buf := make([]byte, size)
+	n.FillBytes(buf)
+
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, ErrMissingParameter
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidParameter
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+func decodeFixedBigInt(s string, size int) (*big.Int, error) {
+	b, err := decodeFixedBytes(s, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+func decodeFixedBytes(s string, size int) ([]byte, error) {
+	if s == "" {
+		return nil, ErrMissingParameter
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) != size {
+		return nil, ErrInvalidParameter
+	}
+
+	return b, nil
+}
@@ -0,0 +1,247 @@
+package jwk_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/jwk"
+	"github.com/furdarius/jwtee/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+type testclaims struct {
+	jwtee.RegisteredClaims
+
+	Name string `json:"name"`
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c testclaims) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(c)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		desc    string
+		data    []byte
+		checker func(t *testing.T, key jwtee.Key, err error)
+	}{
+		{
+			desc: "successful parsing Ed25519 private key",
+			// Example from https://tools.ietf.org/html/rfc8037#appendix-A.1
+			data: []byte(`{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`),
+			checker: func(t *testing.T, key jwtee.Key, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, jwtee.KeyTypeOKP, key.Type())
+				assert.True(t, key.IsPrivate())
+			},
+		},
+		{
+			desc: "successful parsing symmetric key",
+			data: []byte(`{"kty":"oct","kid":"hmac","alg":"HS256","k":"c2VjcmV0"}`),
+			checker: func(t *testing.T, key jwtee.Key, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, jwtee.KeyTypeOct, key.Type())
+				assert.Equal(t, "hmac", key.ID())
+				assert.Equal(t, jwtee.HS256, key.Algorithm())
+				assert.Equal(t, []byte(`secret`), key.Secret())
+			},
+		},
+		{
+			desc: "failed parsing Ed25519 key with mismatched public part",
+			data: []byte(`{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"AAAAAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`),
+			checker: func(t *testing.T, key jwtee.Key, err error) {
+				assert.Equal(t, jwk.ErrInvalidParameter, err)
+			},
+		},
+		{
+			desc: "failed parsing EC key with point not on curve",
+			data: []byte(`{"kty":"EC","crv":"P-256","x":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE","y":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE"}`),
+			checker: func(t *testing.T, key jwtee.Key, err error) {
+				assert.Equal(t, jwk.ErrInvalidParameter, err)
+			},
+		},
+		{
+			desc: "failed parsing EC key with unsupported curve",
+			data: []byte(`{"kty":"EC","crv":"P-192","x":"AQ","y":"AQ"}`),
+			checker: func(t *testing.T, key jwtee.Key, err error) {
+				assert.Equal(t, jwk.ErrUnsupportedCurve, err)
+			},
+		},
+		{
+			desc: "failed parsing RSA key without modulus",
+			data: []byte(`{"kty":"RSA","e":"AQAB"}`),
+			checker: func(t *testing.T, key jwtee.Key, err error) {
+				assert.Equal(t, jwk.ErrMissingParameter, err)
+			},
+		},
+		{
+			desc: "failed parsing unsupported key type",
+			data: []byte(`{"kty":"XYZ"}`),
+			checker: func(t *testing.T, key jwtee.Key, err error) {
+				assert.Equal(t, jwk.ErrUnsupportedKeyType, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			k, err := jwk.Parse(test.data)
+			if err != nil {
+				test.checker(t, jwtee.Key{}, err)
+				return
+			}
+
+			key, err := k.JWTKey()
+			test.checker(t, key, err)
+		})
+	}
+}
+
+func TestFromKey_RoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+
+	tests := []struct {
+		desc   string
+		key    jwtee.Key
+		signer jwtee.Signer
+	}{
+		{
+			desc:   "RSA key",
+			key:    jwtee.NewRSAPrivateKey(rsaKey).WithID("rsa"),
+			signer: signer.NewRS256(),
+		},
+		{
+			desc:   "ECDSA key",
+			key:    jwtee.NewECDSAPrivateKey(ecKey).WithID("ec"),
+			signer: signer.NewES512(),
+		},
+		{
+			desc:   "symmetric key",
+			key:    jwtee.NewSharedSecretKey([]byte(`secret`)).WithID("oct"),
+			signer: signer.NewHS256(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			k, err := jwk.FromKey(test.key)
+			assert.NoError(t, err)
+
+			data, err := json.Marshal(k)
+			assert.NoError(t, err)
+
+			parsed, err := jwk.Parse(data)
+			assert.NoError(t, err)
+			assert.Equal(t, test.key.ID(), parsed.Kid)
+
+			private, err := parsed.JWTKey()
+			assert.NoError(t, err)
+
+			signature, err := test.signer.Sign([]byte(`payload`), private)
+			assert.NoError(t, err)
+
+			verifyKey := private
+			if test.key.Type() != jwtee.KeyTypeOct {
+				verifyKey, err = parsed.Public().JWTKey()
+				assert.NoError(t, err)
+				assert.False(t, verifyKey.IsPrivate())
+			}
+
+			err = test.signer.Verify(signature, []byte(`payload`), verifyKey)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSet_Register(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+
+	ecJWK, err := jwk.FromKey(jwtee.NewECDSAPrivateKey(ecKey).WithID("ec-1"))
+	assert.NoError(t, err)
+
+	set := &jwk.Set{
+		Keys: []*jwk.Key{
+			ecJWK.Public(),
+			{Kty: jwtee.KeyTypeOct, Use: jwk.UseEncryption, Kid: "enc", K: "c2VjcmV0"},
+		},
+	}
+
+	data, err := json.Marshal(set)
+	assert.NoError(t, err)
+
+	parsed, err := jwk.ParseSet(data)
+	assert.NoError(t, err)
+
+	_, ok := parsed.Key("ec-1")
+	assert.True(t, ok)
+
+	ks := jwtee.NewKeySet()
+	err = parsed.Register(ks)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ks.Len(), "encryption key must be skipped")
+
+	parts, err := jwtee.NewTokenBuilder().WithKID("ec-1").Build(testclaims{Name: "John Doe"}, signer.NewES256(), jwtee.NewECDSAPrivateKey(ecKey))
+	assert.NoError(t, err)
+
+	raw, err := parts.MarshalText()
+	assert.NoError(t, err)
+
+	_, err = jwtee.NewVerifyingParser(jwtee.NewJSONParser(), ks).Parse(raw)
+	assert.NoError(t, err)
+}
+
+func TestSet_RegisterSkipsUnusableKeys(t *testing.T) {
+	set, err := jwk.ParseSet([]byte(`{"keys":[
+		{"kty":"oct","kid":"no-alg","k":"c2VjcmV0"},
+		{"kty":"RSA","kid":"rsa-oaep","alg":"RSA-OAEP","n":"AQAB","e":"AQAB"},
+		{"kty":"OKP","kid":"x25519","crv":"X25519","x":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"},
+		{"kty":"RSA","kid":"rsa-hs","alg":"HS256","n":"AQAB","e":"AQAB"},
+		{"kty":"EC","kid":"ec-rs","alg":"RS256","crv":"P-256","x":"AQAB","y":"AQAB"},
+		{"kty":"oct","kid":"hmac","alg":"HS256","k":"c2VjcmV0"}
+	]}`))
+	assert.NoError(t, err)
+
+	ks := jwtee.NewKeySet()
+	err = set.Register(ks)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ks.Len())
+
+	parts, err := jwtee.NewTokenBuilder().WithKID("hmac").Build(testclaims{Name: "John Doe"}, signer.NewHS256(), jwtee.NewSharedSecretKey([]byte(`secret`)))
+	assert.NoError(t, err)
+
+	raw, err := parts.MarshalText()
+	assert.NoError(t, err)
+
+	_, err = jwtee.NewVerifyingParser(jwtee.NewJSONParser(), ks).Parse(raw)
+	assert.NoError(t, err)
+}
+
+func TestSet_RegisterInvalidKey(t *testing.T) {
+	set, err := jwk.ParseSet([]byte(`{"keys":[
+		{"kty":"oct","kid":"hmac","alg":"HS256","k":"c2VjcmV0"},
+		{"kty":"oct","kid":"broken","alg":"HS256","k":"!!!"}
+	]}`))
+	assert.NoError(t, err)
+
+	ks := jwtee.NewKeySet()
+	err = set.Register(ks)
+	assert.Equal(t, jwk.ErrInvalidParameter, err)
+	assert.Equal(t, 0, ks.Len())
+}
@@ -0,0 +1,330 @@
+package jwk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/furdarius/jwtee"
+)
+
+// Block represents RemoteSet errors.
+var (
+	ErrFetchFailed = errors.New("failed to fetch JWK Set")
+)
+
+const (
+	defaultCacheTTL         = time.Hour
+	defaultRefreshRateLimit = 5 * time.Minute
+	defaultFetchTimeout     = 10 * time.Second
+	maxSetSize              = 1 << 20
+)
+
+// RemoteSet implements jwtee.Verifier with JWK Set published at URL (e.g. OpenID Connect jwks_uri).
+// Fetched set is cached according to Cache-Control max-age of the response.
+// When token has unknown kid, set is refetched at most once per refresh rate limit interval
+// to pick up rotated keys. It is safe for concurrent use.
+type RemoteSet struct {
+	url       string
+	client    *http.Client
+	ttl       time.Duration
+	rateLimit time.Duration
+	timeout   time.Duration
+	fallback  jwtee.KIDFallback
+
+	// fetching serializes fetching, it is a semaphore to let waiting be cancelled.
+	fetching  chan struct{}
+	lastFetch time.Time
+
+	mu        sync.RWMutex
+	keys      *jwtee.KeySet
+	expiresAt time.Time
+}
+
+// NewRemoteSet returns new instance of RemoteSet.
+func NewRemoteSet(url string) *RemoteSet {
+	return &RemoteSet{
+		url:       url,
+		client:    &http.Client{Timeout: defaultFetchTimeout},
+		ttl:       defaultCacheTTL,
+		rateLimit: defaultRefreshRateLimit,
+		timeout:   defaultFetchTimeout,
+		fetching:  make(chan struct{}, 1),
+	}
+}
+
+// WithClient setup HTTP client used to fetch JWK Set.
+func (r *RemoteSet) WithClient(client *http.Client) *RemoteSet {
+	r.client = client
+
+	return r
+}
+
+// WithCacheTTL setup how long JWK Set is cached when response has no Cache-Control max-age.
+func (r *RemoteSet) WithCacheTTL(ttl time.Duration) *RemoteSet {
+	r.ttl = ttl
+
+	return r
+}
+
+// WithRefreshRateLimit setup minimal interval between fetches.
+// It limits refetching on unknown kid and is the lower bound of cache lifetime.
+func (r *RemoteSet) WithRefreshRateLimit(interval time.Duration) *RemoteSet {
+	r.rateLimit = interval
+
+	return r
+}
+
+// WithFetchTimeout setup deadline of fetches made during Verify and by Start, zero means no timeout.
+// Verifications wait for the fetch in progress at most this time, so hung server does not block them forever.
+func (r *RemoteSet) WithFetchTimeout(timeout time.Duration) *RemoteSet {
+	r.timeout = timeout
+
+	return r
+}
+
+// WithFallback setup behaviour for tokens without kid header.
+func (r *RemoteSet) WithFallback(fallback jwtee.KIDFallback) *RemoteSet {
+	r.fallback = fallback
+
+	return r
+}
+
+// Verify inherited from jwtee.Verifier.
+func (r *RemoteSet) Verify(parts *jwtee.DecodedParts) error {
+	ctx, cancel := r.withTimeout(context.Background())
+	defer cancel()
+
+	keys, err := r.keySet(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = keys.Verify(parts)
+	if !errors.Is(err, jwtee.ErrUnknownKID) {
+		return err
+	}
+
+	// Keys may have been rotated since the last fetch.
+	keys, err = r.refreshUnknown(ctx)
+	if err != nil {
+		return err
+	}
+
+	return keys.Verify(parts)
+}
+
+// Refresh fetches JWK Set immediately, ignoring cache and rate limit.
+func (r *RemoteSet) Refresh(ctx context.Context) error {
+	err := r.lockFetch(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.unlockFetch()
+
+	return r.fetch(ctx)
+}
+
+// Start refreshes JWK Set in the background before cached one expires,
+// until ctx is done. Failed refreshes are retried after refresh rate limit interval.
+func (r *RemoteSet) Start(ctx context.Context) {
+	go func() {
+		for {
+			r.mu.RLock()
+			wait := time.Until(r.expiresAt)
+			r.mu.RUnlock()
+
+			if wait < r.rateLimit {
+				wait = r.rateLimit
+			}
+
+			timer := time.NewTimer(wait)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+
+				return
+			case <-timer.C:
+			}
+
+			refreshCtx, cancel := r.withTimeout(ctx)
+			_ = r.Refresh(refreshCtx)
+			cancel()
+		}
+	}()
+}
+
+// withTimeout returns ctx limited by fetch timeout.
+func (r *RemoteSet) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, r.timeout)
+}
+
+// lockFetch waits until no other fetch is in progress or ctx is done.
+func (r *RemoteSet) lockFetch(ctx context.Context) error {
+	select {
+	case r.fetching <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %v", ErrFetchFailed, ctx.Err())
+	}
+}
+
+// unlockFetch allows next fetch.
+func (r *RemoteSet) unlockFetch() {
+	<-r.fetching
+}
+
+// keySet returns cached KeySet, fetching it if cache is empty or expired.
+// Stale KeySet is returned if fetch fails.
+func (r *RemoteSet) keySet(ctx context.Context) (*jwtee.KeySet, error) {
+	r.mu.RLock()
+	keys, expiresAt := r.keys, r.expiresAt
+	r.mu.RUnlock()
+
+	if keys != nil && time.Now().Before(expiresAt) {
+		return keys, nil
+	}
+
+	err := r.lockFetch(ctx)
+	if err != nil {
+		if keys != nil {
+			return keys, nil
+		}
+
+		return nil, err
+	}
+	defer r.unlockFetch()
+
+	r.mu.RLock()
+	keys, expiresAt = r.keys, r.expiresAt
+	r.mu.RUnlock()
+
+	// Someone else fetched the set while we were waiting,
+	// or the last fetch failed recently and stale set is still usable.
+	if keys != nil && (time.Now().Before(expiresAt) || time.Since(r.lastFetch) < r.rateLimit) {
+		return keys, nil
+	}
+
+	err = r.fetch(ctx)
+	if err != nil {
+		if keys != nil {
+			return keys, nil
+		}
+
+		return nil, err
+	}
+
+	r.mu.RLock()
+	keys = r.keys
+	r.mu.RUnlock()
+
+	return keys, nil
+}
+
+// refreshUnknown refetches JWK Set unless it was fetched within refresh rate limit interval.
+func (r *RemoteSet) refreshUnknown(ctx context.Context) (*jwtee.KeySet, error) {
+	err := r.lockFetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.unlockFetch()
+
+	if time.Since(r.lastFetch) >= r.rateLimit {
+		err = r.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.RLock()
+	keys := r.keys
+	r.mu.RUnlock()
+
+	return keys, nil
+}
+
+// fetch downloads and installs JWK Set. It must be called with fetch lock held.
+func (r *RemoteSet) fetch(ctx context.Context) error {
+	r.lastFetch = time.Now()
+
+	req, err := http.NewRequest(http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: unexpected status %d", ErrFetchFailed, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSetSize))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+
+	set, err := ParseSet(data)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+
+	keys := jwtee.NewKeySet().WithFallback(r.fallback)
+
+	err = set.Register(keys)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+
+	ttl := r.cacheTTL(resp.Header)
+
+	r.mu.Lock()
+	r.keys = keys
+	r.expiresAt = r.lastFetch.Add(ttl)
+	r.mu.Unlock()
+
+	return nil
+}
+
+// cacheTTL returns lifetime of fetched set according to Cache-Control header.
+// @see https://tools.ietf.org/html/rfc7234#section-5.2.2
+func (r *RemoteSet) cacheTTL(header http.Header) time.Duration {
+	ttl := r.ttl
+	noCache := false
+
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+
+		switch {
+		case directive == "no-cache" || directive == "no-store":
+			noCache = true
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(directive, "max-age="), `"`), 10, 64)
+			if err == nil && seconds >= 0 {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	if noCache || ttl < r.rateLimit {
+		ttl = r.rateLimit
+	}
+
+	return ttl
+}
@@ -0,0 +1,327 @@
+package jwk_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/jwk"
+	"github.com/furdarius/jwtee/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+// jwksServer serves JWK Set built from registered keys and counts requests.
+type jwksServer struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	keys         []*jwk.Key
+	cacheControl string
+	status       int
+	requests     int32
+}
+
+func newJWKSServer() *jwksServer {
+	s := &jwksServer{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.requests, 1)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.cacheControl != "" {
+			w.Header().Set("Cache-Control", s.cacheControl)
+		}
+
+		w.WriteHeader(s.status)
+		_ = json.NewEncoder(w).Encode(jwk.Set{Keys: s.keys})
+	}))
+
+	return s
+}
+
+func (s *jwksServer) setKeys(keys ...*jwk.Key) {
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+}
+
+func (s *jwksServer) count() int {
+	return int(atomic.LoadInt32(&s.requests))
+}
+
+type signingKey struct {
+	private *ecdsa.PrivateKey
+	kid     string
+}
+
+func newSigningKey(t *testing.T, kid string) signingKey {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+
+	return signingKey{private, kid}
+}
+
+func (k signingKey) jwk(t *testing.T) *jwk.Key {
+	key, err := jwk.FromKey(jwtee.NewECDSAPrivateKey(k.private).WithID(k.kid))
+	if err != nil {
+		t.Fatalf("failed to convert key: %v", err)
+	}
+
+	return key.Public()
+}
+
+func (k signingKey) token(t *testing.T) *jwtee.DecodedParts {
+	parts, err := jwtee.NewTokenBuilder().WithKID(k.kid).Build(testclaims{Name: "John Doe"}, signer.NewES256(), jwtee.NewECDSAPrivateKey(k.private))
+	if err != nil {
+		t.Fatalf("failed to build token: %v", err)
+	}
+
+	raw, err := parts.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal token: %v", err)
+	}
+
+	parsed, err := jwtee.NewJSONParser().Parse(raw)
+	if err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+
+	return parsed
+}
+
+func TestRemoteSet_Cache(t *testing.T) {
+	key := newSigningKey(t, "k1")
+
+	server := newJWKSServer()
+	defer server.Close()
+	server.setKeys(key.jwk(t))
+	server.cacheControl = "public, max-age=3600"
+
+	remote := jwk.NewRemoteSet(server.URL).WithRefreshRateLimit(0)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, remote.Verify(key.token(t)))
+	}
+
+	assert.Equal(t, 1, server.count(), "JWK Set must be cached")
+}
+
+func TestRemoteSet_NoCache(t *testing.T) {
+	key := newSigningKey(t, "k1")
+
+	server := newJWKSServer()
+	defer server.Close()
+	server.setKeys(key.jwk(t))
+	server.cacheControl = "no-store"
+
+	remote := jwk.NewRemoteSet(server.URL).WithRefreshRateLimit(0)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, remote.Verify(key.token(t)))
+	}
+
+	assert.Equal(t, 3, server.count())
+}
+
+func TestRemoteSet_UnknownKID(t *testing.T) {
+	oldKey := newSigningKey(t, "old")
+	newKey := newSigningKey(t, "new")
+
+	server := newJWKSServer()
+	defer server.Close()
+	server.setKeys(oldKey.jwk(t))
+
+	remote := jwk.NewRemoteSet(server.URL).WithRefreshRateLimit(time.Hour)
+
+	assert.NoError(t, remote.Verify(oldKey.token(t)))
+	assert.Equal(t, 1, server.count())
+
+	server.setKeys(oldKey.jwk(t), newKey.jwk(t))
+
+	// Rotated key is not picked up within rate limit interval.
+	assert.True(t, errors.Is(remote.Verify(newKey.token(t)), jwtee.ErrUnknownKID))
+	assert.Equal(t, 1, server.count())
+
+	assert.NoError(t, remote.Refresh(context.Background()))
+	assert.NoError(t, remote.Verify(newKey.token(t)))
+	assert.Equal(t, 2, server.count())
+}
+
+func TestRemoteSet_RefetchOnUnknownKID(t *testing.T) {
+	oldKey := newSigningKey(t, "old")
+	newKey := newSigningKey(t, "new")
+
+	server := newJWKSServer()
+	defer server.Close()
+	server.setKeys(oldKey.jwk(t))
+
+	remote := jwk.NewRemoteSet(server.URL).WithRefreshRateLimit(0).WithCacheTTL(time.Hour)
+
+	assert.NoError(t, remote.Verify(oldKey.token(t)))
+
+	server.setKeys(newKey.jwk(t))
+
+	assert.NoError(t, remote.Verify(newKey.token(t)))
+	assert.Equal(t, 2, server.count())
+
+	assert.True(t, errors.Is(remote.Verify(oldKey.token(t)), jwtee.ErrUnknownKID))
+}
+
+func TestRemoteSet_FetchFailed(t *testing.T) {
+	key := newSigningKey(t, "k1")
+
+	server := newJWKSServer()
+	defer server.Close()
+	server.status = http.StatusInternalServerError
+
+	remote := jwk.NewRemoteSet(server.URL)
+
+	err := remote.Verify(key.token(t))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, jwk.ErrFetchFailed))
+}
+
+func TestRemoteSet_FetchTimeout(t *testing.T) {
+	key := newSigningKey(t, "k1")
+
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	remote := jwk.NewRemoteSet(server.URL).WithFetchTimeout(50 * time.Millisecond)
+
+	token := key.token(t)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- remote.Verify(token)
+	}()
+
+	select {
+	case err := <-done:
+		assert.True(t, errors.Is(err, jwk.ErrFetchFailed))
+	case <-time.After(5 * time.Second):
+		t.Fatal("Verify is blocked by slow server")
+	}
+}
+
+func TestRemoteSet_FetchTimeoutWithBackgroundRefresh(t *testing.T) {
+	key := newSigningKey(t, "k1")
+
+	var requests int32
+
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	remote := jwk.NewRemoteSet(server.URL).
+		WithClient(http.DefaultClient).
+		WithRefreshRateLimit(10 * time.Millisecond).
+		WithFetchTimeout(100 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	remote.Start(ctx)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&requests) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	token := key.token(t)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- remote.Verify(token)
+	}()
+
+	select {
+	case err := <-done:
+		assert.True(t, errors.Is(err, jwk.ErrFetchFailed))
+	case <-time.After(5 * time.Second):
+		t.Fatal("Verify is blocked by background refresh")
+	}
+}
+
+func TestRemoteSet_WithoutFetchTimeout(t *testing.T) {
+	key := newSigningKey(t, "k1")
+
+	server := newJWKSServer()
+	defer server.Close()
+	server.setKeys(key.jwk(t))
+
+	remote := jwk.NewRemoteSet(server.URL).WithFetchTimeout(0)
+
+	assert.NoError(t, remote.Verify(key.token(t)))
+}
+
+func TestRemoteSet_Start(t *testing.T) {
+	key := newSigningKey(t, "k1")
+
+	server := newJWKSServer()
+	defer server.Close()
+	server.setKeys(key.jwk(t))
+	server.cacheControl = "max-age=0"
+
+	remote := jwk.NewRemoteSet(server.URL).WithRefreshRateLimit(10 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	remote.Start(ctx)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for server.count() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+
+	assert.True(t, server.count() >= 3, "JWK Set must be refreshed in the background")
+	assert.NoError(t, remote.Verify(key.token(t)))
+}
+
+func TestRemoteSet_VerifyingParser(t *testing.T) {
+	key := newSigningKey(t, "k1")
+
+	server := newJWKSServer()
+	defer server.Close()
+	server.setKeys(key.jwk(t))
+
+	raw, err := key.token(t).MarshalText()
+	assert.NoError(t, err)
+
+	parser := jwtee.NewVerifyingParser(jwtee.NewJSONParser(), jwk.NewRemoteSet(server.URL))
+
+	parts, err := parser.Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, "k1", parts.Header().Kid)
+}
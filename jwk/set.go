@@ -0,0 +1,122 @@
+package jwk
+
+import (
+	"encoding/json"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/signer"
+)
+
+// Set represents JSON Web Key Set.
+// @see https://tools.ietf.org/html/rfc7517#section-5
+type Set struct {
+	Keys []*Key `json:"keys"`
+}
+
+// ParseSet decodes JSON Web Key Set from JSON.
+func ParseSet(data []byte) (*Set, error) {
+	var s Set
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range s.Keys {
+		if k == nil || k.Kty == "" {
+			return nil, ErrMissingParameter
+		}
+	}
+
+	return &s, nil
+}
+
+// Key returns first key with given kid.
+func (s *Set) Key(kid string) (*Key, bool) {
+	for _, k := range s.Keys {
+		if k.Kid == kid {
+			return k, true
+		}
+	}
+
+	return nil, false
+}
+
+// Public returns copy of the Set without private parameters.
+func (s *Set) Public() *Set {
+	public := &Set{
+		Keys: make([]*Key, 0, len(s.Keys)),
+	}
+
+	for _, k := range s.Keys {
+		public.Keys = append(public.Keys, k.Public())
+	}
+
+	return public
+}
+
+// Register adds signature keys from the Set to KeySet.
+// Keys which cannot be used for signature verification are skipped:
+// keys intended for encryption ("use":"enc"), keys without known signature algorithm
+// and keys whose type does not match the algorithm family.
+// Key algorithm is taken from "alg" parameter or inferred from curve.
+// If any usable key cannot be converted then KeySet is left untouched.
+func (s *Set) Register(ks *jwtee.KeySet) error {
+	type entry struct {
+		kid    string
+		signer jwtee.Signer
+		key    jwtee.Key
+	}
+
+	entries := make([]entry, 0, len(s.Keys))
+
+	for _, k := range s.Keys {
+		if k.Use == UseEncryption {
+			continue
+		}
+
+		alg := k.Algorithm()
+		if keyTypeFor(alg) != k.Kty {
+			continue
+		}
+
+		sig, err := signer.ForAlgorithm(alg)
+		if err != nil {
+			continue
+		}
+
+		key, err := k.JWTKey()
+		if err == ErrUnsupportedCurve {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry{k.Kid, sig, key.WithAlgorithm(alg)})
+	}
+
+	for _, e := range entries {
+		ks.Add(e.kid, e.signer, e.key)
+	}
+
+	return nil
+}
+
+// keyTypeFor returns key type required by the signature algorithm.
+// Empty string returns for unknown algorithm.
+func keyTypeFor(alg jwtee.Algorithm) jwtee.KeyType {
+	switch alg {
+	case jwtee.HS256, jwtee.HS384, jwtee.HS512:
+		return jwtee.KeyTypeOct
+	case jwtee.RS256, jwtee.RS384, jwtee.RS512, jwtee.PS256, jwtee.PS384, jwtee.PS512:
+		return jwtee.KeyTypeRSA
+	case jwtee.ES256, jwtee.ES384, jwtee.ES512:
+		return jwtee.KeyTypeEC
+	case jwtee.EdDSA:
+		return jwtee.KeyTypeOKP
+	default:
+		return ""
+	}
+}
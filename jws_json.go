@@ -0,0 +1,480 @@
+package jwtee
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrMalformedJSONSerialization indicates that JWS JSON Serialization has invalid format.
+	ErrMalformedJSONSerialization = errors.New("malformed JWS JSON serialization")
+
+	// ErrHeaderParameterConflict indicates that parameter is present in both protected and unprotected headers.
+	ErrHeaderParameterConflict = errors.New("header parameter is present in both protected and unprotected headers")
+
+	// ErrNoSignatures indicates that JWS has no signatures.
+	ErrNoSignatures = errors.New("no signatures")
+)
+
+// VerifyMode describes how many signatures of JWS JSON Serialization must be valid.
+type VerifyMode int
+
+// VerifyMode constants represents available verification modes.
+const (
+	// VerifyAny requires at least one valid signature.
+	VerifyAny VerifyMode = iota
+
+	// VerifyAll requires every signature to be valid.
+	VerifyAll
+)
+
+// JSONSignature stores one signature of JWS JSON Serialization.
+// Embedded DecodedParts header is the union of protected and unprotected headers,
+// and its payload is the JWS Signing Input of the signature.
+type JSONSignature struct {
+	*DecodedParts
+
+	protected   Header
+	unprotected Header
+
+	encodedProtected []byte
+	rawUnprotected   json.RawMessage
+}
+
+// Protected returns integrity protected header of the signature.
+func (s *JSONSignature) Protected() Header {
+	return s.protected
+}
+
+// Unprotected returns unprotected header of the signature.
+func (s *JSONSignature) Unprotected() Header {
+	return s.unprotected
+}
+
+// JSONParts stores decoded parts of JWS JSON Serialization.
+// @see https://tools.ietf.org/html/rfc7515#section-7.2
+type JSONParts struct {
+	raw            []byte
+	claims         json.RawMessage
+	encodedPayload []byte
+	signatures     []*JSONSignature
+}
+
+// RawClaims returns bytes with decoded claims string.
+func (p *JSONParts) RawClaims() []byte {
+	return p.claims
+}
+
+// Signatures returns all signatures of JWS.
+func (p *JSONParts) Signatures() []*JSONSignature {
+	return p.signatures
+}
+
+// MarshalBinary inherited from encoding.BinaryMarshaler.
+// Parsed JWS is returned as is, built one is serialized in general syntax.
+func (p *JSONParts) MarshalBinary() (data []byte, err error) {
+	if p.raw != nil {
+		return p.raw, nil
+	}
+
+	return p.MarshalGeneral()
+}
+
+// MarshalGeneral serializes JWS in general JSON syntax.
+// @see https://tools.ietf.org/html/rfc7515#section-7.2.1
+func (p *JSONParts) MarshalGeneral() ([]byte, error) {
+	type signature struct {
+		Protected string          `json:"protected,omitempty"`
+		Header    json.RawMessage `json:"header,omitempty"`
+		Signature string          `json:"signature"`
+	}
+
+	general := struct {
+		Payload    string      `json:"payload"`
+		Signatures []signature `json:"signatures"`
+	}{
+		Payload:    string(p.encodedPayload),
+		Signatures: make([]signature, 0, len(p.signatures)),
+	}
+
+	for _, s := range p.signatures {
+		general.Signatures = append(general.Signatures, signature{
+			Protected: string(s.encodedProtected),
+			Header:    s.rawUnprotected,
+			Signature: base64.RawURLEncoding.EncodeToString(s.Signature()),
+		})
+	}
+
+	return json.Marshal(general)
+}
+
+// MarshalFlattened serializes JWS with single signature in flattened JSON syntax.
+// @see https://tools.ietf.org/html/rfc7515#section-7.2.2
+func (p *JSONParts) MarshalFlattened() ([]byte, error) {
+	if len(p.signatures) != 1 {
+		return nil, ErrMalformedJSONSerialization
+	}
+
+	s := p.signatures[0]
+
+	return json.Marshal(struct {
+		Payload   string          `json:"payload"`
+		Protected string          `json:"protected,omitempty"`
+		Header    json.RawMessage `json:"header,omitempty"`
+		Signature string          `json:"signature"`
+	}{
+		Payload:   string(p.encodedPayload),
+		Protected: string(s.encodedProtected),
+		Header:    s.rawUnprotected,
+		Signature: base64.RawURLEncoding.EncodeToString(s.Signature()),
+	})
+}
+
+// jsonSerialization describes both general and flattened JWS JSON Serialization syntax.
+type jsonSerialization struct {
+	Payload    *string             `json:"payload"`
+	Signatures []jsonSignatureData `json:"signatures"`
+
+	jsonSignatureData
+}
+
+type jsonSignatureData struct {
+	Protected string          `json:"protected"`
+	Header    json.RawMessage `json:"header"`
+	Signature *string         `json:"signature"`
+}
+
+// JWSJSONParser used to parse JWS JSON Serialization in general or flattened syntax.
+type JWSJSONParser struct{}
+
+// NewJWSJSONParser returns new instance of JWSJSONParser.
+func NewJWSJSONParser() *JWSJSONParser {
+	return &JWSJSONParser{}
+}
+
+// Parse decodes JWS JSON Serialization.
+// Returned errors are *TokenError of KindMalformed wrapping the cause.
+func (p *JWSJSONParser) Parse(data []byte) (*JSONParts, error) {
+	var s jsonSerialization
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, newTokenError(KindMalformed, SegmentToken, err)
+	}
+
+	if s.Payload == nil {
+		return nil, newTokenError(KindMalformed, SegmentClaims, ErrMalformedJSONSerialization)
+	}
+
+	flattened := s.Signature != nil
+	if flattened && s.Signatures != nil {
+		return nil, newTokenError(KindMalformed, SegmentToken, ErrMalformedJSONSerialization)
+	}
+
+	signatures := s.Signatures
+	if flattened {
+		signatures = []jsonSignatureData{s.jsonSignatureData}
+	}
+
+	if len(signatures) == 0 {
+		return nil, newTokenError(KindMalformed, SegmentSignature, ErrNoSignatures)
+	}
+
+	claims, err := base64.RawURLEncoding.DecodeString(*s.Payload)
+	if err != nil {
+		return nil, newTokenError(KindMalformed, SegmentClaims, err)
+	}
+
+	parts := &JSONParts{
+		raw:            data,
+		claims:         claims,
+		encodedPayload: []byte(*s.Payload),
+		signatures:     make([]*JSONSignature, 0, len(signatures)),
+	}
+
+	for _, sig := range signatures {
+		signature, err := p.parseSignature(sig, parts)
+		if err != nil {
+			return nil, err
+		}
+
+		parts.signatures = append(parts.signatures, signature)
+	}
+
+	return parts, nil
+}
+
+func (p *JWSJSONParser) parseSignature(sig jsonSignatureData, parts *JSONParts) (*JSONSignature, error) {
+	if sig.Signature == nil {
+		return nil, newTokenError(KindMalformed, SegmentSignature, ErrMalformedJSONSerialization)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(*sig.Signature)
+	if err != nil {
+		return nil, newTokenError(KindMalformed, SegmentSignature, err)
+	}
+
+	rawProtected, err := base64.RawURLEncoding.DecodeString(sig.Protected)
+	if err != nil {
+		return nil, newTokenError(KindMalformed, SegmentHeader, err)
+	}
+
+	s := &JSONSignature{
+		encodedProtected: []byte(sig.Protected),
+		rawUnprotected:   sig.Header,
+	}
+
+	if len(rawProtected) > 0 {
+		err = json.Unmarshal(rawProtected, &s.protected)
+		if err != nil {
+			return nil, newTokenError(KindMalformed, SegmentHeader, err)
+		}
+	}
+
+	if len(sig.Header) > 0 {
+		err = json.Unmarshal(sig.Header, &s.unprotected)
+		if err != nil {
+			return nil, newTokenError(KindMalformed, SegmentHeader, err)
+		}
+	}
+
+	header, rawHeader, err := mergeHeaders(rawProtected, sig.Header)
+	if err != nil {
+		return nil, newTokenError(KindMalformed, SegmentHeader, err)
+	}
+
+	payload := concat(s.encodedProtected, parts.encodedPayload)
+
+	s.DecodedParts = &DecodedParts{
+		raw:       concat(payload, []byte(*sig.Signature)),
+		header:    header,
+		rawHeader: rawHeader,
+		claims:    parts.claims,
+		payload:   payload,
+		signature: signature,
+	}
+
+	return s, nil
+}
+
+// mergeHeaders returns union of protected and unprotected headers and its raw JSON.
+// @see https://tools.ietf.org/html/rfc7515#section-7.2.1
+func mergeHeaders(protected, unprotected []byte) (Header, []byte, error) {
+	var h Header
+
+	if len(protected) == 0 || len(unprotected) == 0 {
+		raw := protected
+		if len(raw) == 0 {
+			raw = unprotected
+		}
+
+		if len(raw) == 0 {
+			return h, []byte("{}"), nil
+		}
+
+		return h, raw, json.Unmarshal(raw, &h)
+	}
+
+	var protectedParams, unprotectedParams map[string]json.RawMessage
+
+	err := json.Unmarshal(protected, &protectedParams)
+	if err != nil {
+		return h, nil, err
+	}
+
+	err = json.Unmarshal(unprotected, &unprotectedParams)
+	if err != nil {
+		return h, nil, err
+	}
+
+	if protectedParams == nil {
+		protectedParams = make(map[string]json.RawMessage, len(unprotectedParams))
+	}
+
+	for name, value := range unprotectedParams {
+		if _, ok := protectedParams[name]; ok {
+			return h, nil, ErrHeaderParameterConflict
+		}
+
+		protectedParams[name] = value
+	}
+
+	raw, err := json.Marshal(protectedParams)
+	if err != nil {
+		return h, nil, err
+	}
+
+	return h, raw, json.Unmarshal(raw, &h)
+}
+
+// concat joins two parts with separator.
+func concat(p1, p2 []byte) []byte {
+	buf := make([]byte, len(p1)+len(p2)+1)
+	copy(buf, p1)
+	buf[len(p1)] = sep
+	copy(buf[len(p1)+1:], p2)
+
+	return buf
+}
+
+// JWSJSONVerifyingParser used to parse and then verify JWS JSON Serialization.
+type JWSJSONVerifyingParser struct {
+	parser   *JWSJSONParser
+	verifier Verifier
+	mode     VerifyMode
+}
+
+// NewJWSJSONVerifyingParser returns new instance of JWSJSONVerifyingParser.
+// Each signature is verified with verifier, which is usually KeySet.
+func NewJWSJSONVerifyingParser(parser *JWSJSONParser, verifier Verifier, mode VerifyMode) *JWSJSONVerifyingParser {
+	return &JWSJSONVerifyingParser{parser, verifier, mode}
+}
+
+// Parse decodes and verifies JWS JSON Serialization according to verification mode.
+// If verification failed then error of the first failed signature returns.
+func (p *JWSJSONVerifyingParser) Parse(data []byte) (*JSONParts, error) {
+	parts, err := p.parser.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var firstErr error
+
+	for _, s := range parts.signatures {
+		err = p.verifier.Verify(s.DecodedParts)
+
+		if err == nil && p.mode == VerifyAny {
+			return parts, nil
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+
+			if p.mode == VerifyAll {
+				break
+			}
+		}
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return parts, nil
+}
+
+// JWSJSONBuilder used to build JWS JSON Serialization signed by several keys.
+type JWSJSONBuilder struct {
+	signers []jsonSigner
+}
+
+type jsonSigner struct {
+	signer      Signer
+	key         Key
+	protected   Header
+	unprotected Header
+}
+
+// NewJWSJSONBuilder returns new instance of JWSJSONBuilder.
+func NewJWSJSONBuilder() *JWSJSONBuilder {
+	return &JWSJSONBuilder{}
+}
+
+// AddSignature adds signature computed with signer and key.
+// Alg is always set in protected header, and kid of the key is set if neither header has it.
+func (b *JWSJSONBuilder) AddSignature(signer Signer, key Key, protected, unprotected Header) *JWSJSONBuilder {
+	protected.Alg = signer.GetAlgorithmID()
+	unprotected.Alg = ""
+
+	if protected.Kid == "" && unprotected.Kid == "" {
+		protected.Kid = key.ID()
+	}
+
+	b.signers = append(b.signers, jsonSigner{signer, key, protected, unprotected})
+
+	return b
+}
+
+// Build used to construct and sign JWS JSON Serialization.
+func (b *JWSJSONBuilder) Build(claims encoding.BinaryMarshaler) (*JSONParts, error) {
+	if len(b.signers) == 0 {
+		return nil, ErrNoSignatures
+	}
+
+	rawClaims, err := claims.MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode claims")
+	}
+
+	parts := &JSONParts{
+		claims:         rawClaims,
+		encodedPayload: encodeSegment(rawClaims),
+		signatures:     make([]*JSONSignature, 0, len(b.signers)),
+	}
+
+	for _, s := range b.signers {
+		signature, err := b.sign(s, parts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to sign payload")
+		}
+
+		parts.signatures = append(parts.signatures, signature)
+	}
+
+	return parts, nil
+}
+
+func (b *JWSJSONBuilder) sign(s jsonSigner, parts *JSONParts) (*JSONSignature, error) {
+	rawProtected, err := json.Marshal(s.protected)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUnprotected, err := json.Marshal(s.unprotected)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(rawUnprotected) == "{}" {
+		rawUnprotected = nil
+	}
+
+	header, rawHeader, err := mergeHeaders(rawProtected, rawUnprotected)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedProtected := encodeSegment(rawProtected)
+	payload := concat(encodedProtected, parts.encodedPayload)
+
+	signature, err := s.signer.Sign(payload, s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONSignature{
+		DecodedParts: &DecodedParts{
+			raw:       concat(payload, encodeSegment(signature)),
+			header:    header,
+			rawHeader: rawHeader,
+			claims:    parts.claims,
+			payload:   payload,
+			signature: signature,
+		},
+		protected:        s.protected,
+		unprotected:      s.unprotected,
+		encodedProtected: encodedProtected,
+		rawUnprotected:   rawUnprotected,
+	}, nil
+}
+
+// encodeSegment returns base64url encoded data.
+func encodeSegment(data []byte) []byte {
+	encoded := make([]byte, base64.RawURLEncoding.EncodedLen(len(data)))
+	base64.RawURLEncoding.Encode(encoded, data)
+
+	return encoded
+}
@@ -0,0 +1,189 @@
+package jwtee
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnsupportedKey indicates that key material type is not supported.
+	ErrUnsupportedKey = errors.New("unsupported key type")
+)
+
+// KeyType describes cryptographic family of the key.
+// @see https://tools.ietf.org/html/rfc7518#section-6.1
+type KeyType string
+
+// KeyType constants represents available key types values.
+const (
+	KeyTypeOct KeyType = "oct"
+	KeyTypeRSA KeyType = "RSA"
+	KeyTypeEC  KeyType = "EC"
+	KeyTypeOKP KeyType = "OKP"
+)
+
+// Key stores signing key data.
+// It holds either shared secret or asymmetric key material,
+// optionally annotated with key ID and intended algorithm.
+type Key struct {
+	typ     KeyType
+	alg     Algorithm
+	kid     string
+	secret  []byte
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// NewSharedSecretKey returns Key with secret inside.
+func NewSharedSecretKey(secret []byte) Key {
+	return Key{
+		typ:    KeyTypeOct,
+		secret: secret,
+	}
+}
+
+// NewRSAPrivateKey returns Key used to sign and verify with RSA private key.
+func NewRSAPrivateKey(private *rsa.PrivateKey) Key {
+	return Key{
+		typ:     KeyTypeRSA,
+		private: private,
+		public:  &private.PublicKey,
+	}
+}
+
+// NewRSAPublicKey returns Key used to verify with RSA public key.
+func NewRSAPublicKey(public *rsa.PublicKey) Key {
+	return Key{
+		typ:    KeyTypeRSA,
+		public: public,
+	}
+}
+
+// NewECDSAPrivateKey returns Key used to sign and verify with ECDSA private key.
+func NewECDSAPrivateKey(private *ecdsa.PrivateKey) Key {
+	return Key{
+		typ:     KeyTypeEC,
+		private: private,
+		public:  &private.PublicKey,
+	}
+}
+
+// NewECDSAPublicKey returns Key used to verify with ECDSA public key.
+func NewECDSAPublicKey(public *ecdsa.PublicKey) Key {
+	return Key{
+		typ:    KeyTypeEC,
+		public: public,
+	}
+}
+
+// NewEd25519PrivateKey returns Key used to sign and verify with Ed25519 private key.
+func NewEd25519PrivateKey(private ed25519.PrivateKey) Key {
+	return Key{
+		typ:     KeyTypeOKP,
+		private: private,
+		public:  private.Public(),
+	}
+}
+
+// NewEd25519PublicKey returns Key used to verify with Ed25519 public key.
+func NewEd25519PublicKey(public ed25519.PublicKey) Key {
+	return Key{
+		typ:    KeyTypeOKP,
+		public: public,
+	}
+}
+
+// NewPrivateKey returns Key backed by any crypto.Signer (e.g. HSM or KMS handle)
+// whose public part is RSA, ECDSA or Ed25519 key.
+func NewPrivateKey(private crypto.Signer) (Key, error) {
+	typ, err := keyTypeOf(private.Public())
+	if err != nil {
+		return Key{}, err
+	}
+
+	return Key{
+		typ:     typ,
+		private: private,
+		public:  private.Public(),
+	}, nil
+}
+
+// NewPublicKey returns Key used to verify with RSA, ECDSA or Ed25519 public key.
+func NewPublicKey(public crypto.PublicKey) (Key, error) {
+	typ, err := keyTypeOf(public)
+	if err != nil {
+		return Key{}, err
+	}
+
+	return Key{
+		typ:    typ,
+		public: public,
+	}, nil
+}
+
+func keyTypeOf(public crypto.PublicKey) (KeyType, error) {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		return KeyTypeRSA, nil
+	case *ecdsa.PublicKey:
+		return KeyTypeEC, nil
+	case ed25519.PublicKey:
+		return KeyTypeOKP, nil
+	default:
+		return "", ErrUnsupportedKey
+	}
+}
+
+// WithID returns copy of the Key with kid (key ID).
+func (k Key) WithID(kid string) Key {
+	k.kid = kid
+
+	return k
+}
+
+// WithAlgorithm returns copy of the Key restricted to be used with given algorithm.
+func (k Key) WithAlgorithm(alg Algorithm) Key {
+	k.alg = alg
+
+	return k
+}
+
+// Type returns key's type.
+func (k Key) Type() KeyType {
+	return k.typ
+}
+
+// Algorithm returns algorithm the key is intended to be used with,
+// or empty string if key is not restricted.
+func (k Key) Algorithm() Algorithm {
+	return k.alg
+}
+
+// ID returns key's kid (key ID).
+func (k Key) ID() string {
+	return k.kid
+}
+
+// IsPrivate returns true if key can be used for signing.
+func (k Key) IsPrivate() bool {
+	return k.private != nil || k.secret != nil
+}
+
+// Secret returns key's secret.
+func (k Key) Secret() []byte {
+	return k.secret
+}
+
+// PrivateKey returns key's private part, or nil if key has no private part.
+func (k Key) PrivateKey() crypto.Signer {
+	return k.private
+}
+
+// PublicKey returns key's public part, or nil if key has no public part.
+func (k Key) PublicKey() crypto.PublicKey {
+	return k.public
+}
@@ -0,0 +1,104 @@
+package jwtee_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate Ed25519 key: %v", err)
+	}
+
+	tests := []struct {
+		desc    string
+		private crypto.Signer
+		checker func(t *testing.T, key jwtee.Key, err error)
+	}{
+		{
+			desc:    "RSA private key",
+			private: rsaKey,
+			checker: func(t *testing.T, key jwtee.Key, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, jwtee.KeyTypeRSA, key.Type())
+				assert.Equal(t, &rsaKey.PublicKey, key.PublicKey())
+				assert.True(t, key.IsPrivate())
+			},
+		},
+		{
+			desc:    "ECDSA private key",
+			private: ecKey,
+			checker: func(t *testing.T, key jwtee.Key, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, jwtee.KeyTypeEC, key.Type())
+				assert.Equal(t, &ecKey.PublicKey, key.PublicKey())
+				assert.True(t, key.IsPrivate())
+			},
+		},
+		{
+			desc:    "Ed25519 private key",
+			private: edKey,
+			checker: func(t *testing.T, key jwtee.Key, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, jwtee.KeyTypeOKP, key.Type())
+				assert.Equal(t, edKey.Public(), key.PublicKey())
+				assert.True(t, key.IsPrivate())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			key, err := jwtee.NewPrivateKey(test.private)
+			test.checker(t, key, err)
+		})
+	}
+}
+
+func TestNewPublicKey(t *testing.T) {
+	_, err := jwtee.NewPublicKey([]byte(`secret`))
+	assert.Equal(t, jwtee.ErrUnsupportedKey, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+
+	key, err := jwtee.NewPublicKey(&ecKey.PublicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, jwtee.KeyTypeEC, key.Type())
+	assert.False(t, key.IsPrivate())
+	assert.Nil(t, key.PrivateKey())
+}
+
+func TestKey_WithIDAndAlgorithm(t *testing.T) {
+	key := jwtee.NewSharedSecretKey([]byte(`secret`))
+	annotated := key.WithID("key-1").WithAlgorithm(jwtee.HS256)
+
+	assert.Equal(t, jwtee.KeyTypeOct, annotated.Type())
+	assert.Equal(t, "key-1", annotated.ID())
+	assert.Equal(t, jwtee.HS256, annotated.Algorithm())
+	assert.Equal(t, []byte(`secret`), annotated.Secret())
+
+	assert.Equal(t, "", key.ID(), "original key must stay untouched")
+	assert.Equal(t, jwtee.Algorithm(""), key.Algorithm(), "original key must stay untouched")
+}
@@ -0,0 +1,152 @@
+package jwtee
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrKIDMissed indicates that token has no kid header, but KeySet requires it.
+	ErrKIDMissed = errors.New("token kid header missed")
+
+	// ErrUnknownKID indicates that there is no key registered for the token kid.
+	ErrUnknownKID = errors.New("no key registered for the token kid")
+)
+
+// KIDFallback describes how KeySet verifies tokens without kid header.
+type KIDFallback int
+
+// KIDFallback constants represents available fallback behaviours.
+const (
+	// RejectMissingKID rejects tokens without kid header with ErrKIDMissed.
+	RejectMissingKID KIDFallback = iota
+
+	// UseDefaultKey verifies tokens without kid header with the default key.
+	UseDefaultKey
+
+	// TryAllKeys verifies tokens without kid header with every key registered for the token algorithm.
+	TryAllKeys
+)
+
+// KeySet implements Verifier with set of keys selected by kid (key ID) header.
+// It is safe for concurrent use, so keys can be added and removed at runtime
+// to rotate signing keys.
+type KeySet struct {
+	mu         sync.RWMutex
+	verifiers  map[string]*PartsVerifier
+	fallback   KIDFallback
+	defaultKID string
+}
+
+// NewKeySet returns new instance of KeySet.
+func NewKeySet() *KeySet {
+	return &KeySet{
+		verifiers: make(map[string]*PartsVerifier),
+	}
+}
+
+// WithFallback setup behaviour for tokens without kid header.
+func (s *KeySet) WithFallback(fallback KIDFallback) *KeySet {
+	s.mu.Lock()
+	s.fallback = fallback
+	s.mu.Unlock()
+
+	return s
+}
+
+// WithDefault setup kid of the key used when token has no kid header and UseDefaultKey fallback is set.
+func (s *KeySet) WithDefault(kid string) *KeySet {
+	s.mu.Lock()
+	s.defaultKID = kid
+	s.mu.Unlock()
+
+	return s
+}
+
+// Add registers key for kid, replacing previously registered one.
+// Tokens with the kid are verified with signer algorithm only.
+func (s *KeySet) Add(kid string, signer Signer, key Key) {
+	s.mu.Lock()
+	s.verifiers[kid] = NewPartsVerifier(signer, key)
+	s.mu.Unlock()
+}
+
+// Remove unregisters key for kid.
+func (s *KeySet) Remove(kid string) {
+	s.mu.Lock()
+	delete(s.verifiers, kid)
+	s.mu.Unlock()
+}
+
+// Has returns true if key for kid is registered.
+func (s *KeySet) Has(kid string) bool {
+	s.mu.RLock()
+	_, ok := s.verifiers[kid]
+	s.mu.RUnlock()
+
+	return ok
+}
+
+// Len returns number of registered keys.
+func (s *KeySet) Len() int {
+	s.mu.RLock()
+	n := len(s.verifiers)
+	s.mu.RUnlock()
+
+	return n
+}
+
+// Verify inherited from Verifier.
+// If there is no key for the token kid then ErrUnknownKID returns.
+func (s *KeySet) Verify(parts *DecodedParts) error {
+	kid := parts.Header().Kid
+
+	s.mu.RLock()
+	fallback := s.fallback
+
+	if kid == "" {
+		switch fallback {
+		case UseDefaultKey:
+			kid = s.defaultKID
+		case TryAllKeys:
+			verifiers := make([]*PartsVerifier, 0, len(s.verifiers))
+			for _, v := range s.verifiers {
+				verifiers = append(verifiers, v)
+			}
+			s.mu.RUnlock()
+
+			return s.verifyAny(parts, verifiers)
+		default:
+			s.mu.RUnlock()
+
+			return newTokenError(KindUnverifiable, SegmentHeader, ErrKIDMissed)
+		}
+	}
+
+	verifier, ok := s.verifiers[kid]
+	s.mu.RUnlock()
+
+	if !ok {
+		return newTokenError(KindUnverifiable, SegmentHeader, ErrUnknownKID)
+	}
+
+	return verifier.Verify(parts)
+}
+
+func (s *KeySet) verifyAny(parts *DecodedParts, verifiers []*PartsVerifier) error {
+	err := newTokenError(KindUnverifiable, SegmentHeader, ErrUnknownKID)
+
+	for _, verifier := range verifiers {
+		if verifier.signer.GetAlgorithmID() != parts.Header().Alg {
+			continue
+		}
+
+		err = verifier.Verify(parts)
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
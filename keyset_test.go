@@ -0,0 +1,184 @@
+package jwtee_test
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildToken(t *testing.T, builder *jwtee.TokenBuilder, s jwtee.Signer, key jwtee.Key) []byte {
+	parts, err := builder.Build(testclaims{Name: "John Doe"}, s, key)
+	if err != nil {
+		t.Fatalf("failed to build token: %v", err)
+	}
+
+	raw, err := parts.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal token: %v", err)
+	}
+
+	return raw
+}
+
+func TestKeySet_Verify(t *testing.T) {
+	oldKey := jwtee.NewSharedSecretKey([]byte(`old-secret`))
+	newKey := jwtee.NewSharedSecretKey([]byte(`new-secret`))
+
+	oldToken := buildToken(t, jwtee.NewTokenBuilder().WithKID("old"), signer.NewHS256(), oldKey)
+	newToken := buildToken(t, jwtee.NewTokenBuilder().WithKID("new"), signer.NewHS512(), newKey)
+	unknownToken := buildToken(t, jwtee.NewTokenBuilder().WithKID("unknown"), signer.NewHS256(), oldKey)
+	noKIDToken := buildToken(t, jwtee.NewTokenBuilder(), signer.NewHS512(), newKey)
+	confusedToken := buildToken(t, jwtee.NewTokenBuilder().WithKID("old"), signer.NewHS512(), oldKey)
+
+	newKeySet := func() *jwtee.KeySet {
+		ks := jwtee.NewKeySet()
+		ks.Add("old", signer.NewHS256(), oldKey)
+		ks.Add("new", signer.NewHS512(), newKey)
+
+		return ks
+	}
+
+	tests := []struct {
+		desc    string
+		jwt     []byte
+		keySet  *jwtee.KeySet
+		checker func(t *testing.T, err error)
+	}{
+		{
+			desc:   "successful verifying with old key",
+			jwt:    oldToken,
+			keySet: newKeySet(),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:   "successful verifying with new key",
+			jwt:    newToken,
+			keySet: newKeySet(),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:   "failed verifying with unknown kid",
+			jwt:    unknownToken,
+			keySet: newKeySet(),
+			checker: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, jwtee.ErrUnknownKID))
+			},
+		},
+		{
+			desc:   "failed verifying with algorithm differs from the key one",
+			jwt:    confusedToken,
+			keySet: newKeySet(),
+			checker: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, jwtee.ErrAlgorithmMismatch))
+			},
+		},
+		{
+			desc:   "failed verifying without kid",
+			jwt:    noKIDToken,
+			keySet: newKeySet(),
+			checker: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, jwtee.ErrKIDMissed))
+			},
+		},
+		{
+			desc:   "successful verifying without kid with default key",
+			jwt:    noKIDToken,
+			keySet: newKeySet().WithFallback(jwtee.UseDefaultKey).WithDefault("new"),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:   "failed verifying without kid with wrong default key",
+			jwt:    noKIDToken,
+			keySet: newKeySet().WithFallback(jwtee.UseDefaultKey).WithDefault("old"),
+			checker: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, jwtee.ErrAlgorithmMismatch))
+			},
+		},
+		{
+			desc:   "successful verifying without kid trying all keys",
+			jwt:    noKIDToken,
+			keySet: newKeySet().WithFallback(jwtee.TryAllKeys),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:   "failed verifying without kid trying all keys",
+			jwt:    buildToken(t, jwtee.NewTokenBuilder(), signer.NewHS512(), oldKey),
+			keySet: newKeySet().WithFallback(jwtee.TryAllKeys),
+			checker: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, jwtee.ErrInvalidSignature))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := jwtee.NewVerifyingParser(jwtee.NewJSONParser(), test.keySet).Parse(test.jwt)
+			test.checker(t, err)
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	key := jwtee.NewSharedSecretKey([]byte(`secret`))
+	token := buildToken(t, jwtee.NewTokenBuilder().WithKID("k1"), signer.NewHS256(), key)
+	parser := jwtee.NewJSONParser()
+
+	parts, err := parser.Parse(token)
+	assert.NoError(t, err)
+
+	ks := jwtee.NewKeySet()
+	assert.True(t, errors.Is(ks.Verify(parts), jwtee.ErrUnknownKID))
+
+	ks.Add("k1", signer.NewHS256(), key)
+	assert.True(t, ks.Has("k1"))
+	assert.NoError(t, ks.Verify(parts))
+
+	ks.Remove("k1")
+	assert.Equal(t, 0, ks.Len())
+	assert.True(t, errors.Is(ks.Verify(parts), jwtee.ErrUnknownKID))
+}
+
+func TestKeySet_Concurrent(t *testing.T) {
+	key := jwtee.NewSharedSecretKey([]byte(`secret`))
+	token := buildToken(t, jwtee.NewTokenBuilder().WithKID("stable"), signer.NewHS256(), key)
+
+	parts, err := jwtee.NewJSONParser().Parse(token)
+	assert.NoError(t, err)
+
+	ks := jwtee.NewKeySet()
+	ks.Add("stable", signer.NewHS256(), key)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+
+			kid := strconv.Itoa(i)
+			ks.Add(kid, signer.NewHS256(), key)
+			ks.Remove(kid)
+		}(i)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, ks.Verify(parts))
+		}()
+	}
+
+	wg.Wait()
+}
@@ -4,14 +4,31 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"io"
+
 	"github.com/pkg/errors"
 )
 
 const sep byte = '.'
 
+// DefaultMaxTokenLength is maximum token length accepted by strict JSONParser by default.
+const DefaultMaxTokenLength = 16 * 1024
+
 var (
 	// ErrPartMissed indicates that token has invalid format
 	ErrPartMissed = errors.New("one of token parts missed")
+
+	// ErrTooManyParts indicates that token has more than three parts.
+	ErrTooManyParts = errors.New("token has too many parts")
+
+	// ErrTokenTooLong indicates that token length exceeds the maximum.
+	ErrTokenTooLong = errors.New("token is too long")
+
+	// ErrDuplicateKey indicates that header or claims contain duplicate JSON key.
+	ErrDuplicateKey = errors.New("duplicate JSON key")
+
+	// ErrUnsupportedCritical indicates that header has critical parameter which is not understood.
+	ErrUnsupportedCritical = errors.New("unsupported critical header parameter")
 )
 
 // Parser used to take JWT apart.
@@ -20,79 +37,337 @@ type Parser interface {
 }
 
 // JSONParser used to parse JWT token.
-type JSONParser struct{}
+type JSONParser struct {
+	strict    bool
+	maxLength int
+	critical  map[string]bool
+
+	// headers caches decoded headers, nil if disabled.
+	headers *headerCache
+}
 
-// NewJSONParser returns new instance of JSONParser.
+// NewJSONParser returns new instance of JSONParser,
+// caching up to DefaultHeaderCacheSize decoded headers.
 func NewJSONParser() *JSONParser {
-	return &JSONParser{}
+	return &JSONParser{
+		headers: newHeaderCache(DefaultHeaderCacheSize),
+	}
+}
+
+// WithHeaderCache setup maximum number of cached decoded headers, zero disables caching.
+// Headers longer than 1 KB are never cached.
+func (p *JSONParser) WithHeaderCache(size int) *JSONParser {
+	p.headers = nil
+
+	if size > 0 {
+		p.headers = newHeaderCache(size)
+	}
+
+	return p
+}
+
+// Strict enables strict mode of compact serialization parsing.
+// Token must have exactly three parts, must not be longer than DefaultMaxTokenLength
+// unless other limit is set with WithMaxLength, header and claims must not contain
+// duplicate JSON keys and every "crit" header parameter must be understood.
+func (p *JSONParser) Strict() *JSONParser {
+	p.strict = true
+	p.resetCache()
+
+	if p.maxLength == 0 {
+		p.maxLength = DefaultMaxTokenLength
+	}
+
+	return p
+}
+
+// WithMaxLength setup maximum token length, zero means unlimited.
+// If token is longer then ErrTokenTooLong returns.
+func (p *JSONParser) WithMaxLength(maxLength int) *JSONParser {
+	p.maxLength = maxLength
+
+	return p
+}
+
+// WithCritical setup extension header parameters understood by the application,
+// which are allowed to be listed in "crit" header in strict mode.
+func (p *JSONParser) WithCritical(params ...string) *JSONParser {
+	if p.critical == nil {
+		p.critical = make(map[string]bool, len(params))
+	}
+
+	for _, param := range params {
+		p.critical[param] = true
+	}
+
+	p.resetCache()
+
+	return p
+}
+
+// resetCache drops headers decoded with previous settings.
+func (p *JSONParser) resetCache() {
+	if p.headers != nil {
+		p.headers = newHeaderCache(p.headers.size)
+	}
 }
 
 // Parse splits, decode and memoize JWT parts.
+// Returned parts do not share memory with header cache, so they are safe to modify.
 func (p *JSONParser) Parse(jwt json.RawMessage) (*DecodedParts, error) {
+	t := &DecodedParts{}
+
+	err := p.ParseInto(t, jwt)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.headers != nil {
+		t.header = t.header.clone()
+		t.rawHeader = append([]byte(nil), t.rawHeader...)
+	}
+
+	return t, nil
+}
+
+// ParseInto splits, decode and memoize JWT parts into dst, reusing its buffers.
+// Previous content of dst becomes invalid. If header cache is enabled then Header and RawHeader
+// of dst share memory with the cache and other tokens, so they must not be modified.
+// Parsing does not allocate in steady state unless strict mode is enabled.
+func (p *JSONParser) ParseInto(dst *DecodedParts, jwt json.RawMessage) error {
+	if p.maxLength > 0 && len(jwt) > p.maxLength {
+		return newTokenError(KindMalformed, SegmentToken, ErrTokenTooLong)
+	}
+
+	if p.strict && bytes.Count(jwt, []byte{sep}) > 2 {
+		return newTokenError(KindMalformed, SegmentToken, ErrTooManyParts)
+	}
+
 	firstDot := bytes.IndexByte(jwt, sep)
 	lastDot := bytes.LastIndexByte(jwt, sep)
 
 	if lastDot <= firstDot {
-		return nil, ErrPartMissed
+		return newTokenError(KindMalformed, SegmentToken, ErrPartMissed)
+	}
+
+	encodedHeader := jwt[:firstDot]
+	encodedClaims := jwt[firstDot+1 : lastDot]
+	encodedSignature := jwt[lastDot+1:]
+
+	var (
+		cached cachedHeader
+		found  bool
+	)
+
+	if p.headers != nil {
+		cached, found = p.headers.get(encodedHeader)
+	}
+
+	size := base64.RawURLEncoding.DecodedLen(len(encodedClaims)) +
+		base64.RawURLEncoding.DecodedLen(len(encodedSignature))
+	if !found {
+		size += base64.RawURLEncoding.DecodedLen(len(encodedHeader))
+	}
+
+	decoded := dst.buf[:0]
+	if cap(decoded) < size {
+		decoded = make([]byte, size)
 	}
 
-	decoded := make([]byte, len(jwt))
+	decoded = decoded[:size]
+	dst.buf = decoded
+
+	headerN := 0
+
+	if !found {
+		var err error
+
+		cached, err = p.decodeHeader(decoded, encodedHeader)
+		if err != nil {
+			return err
+		}
 
-	headerN, err := base64.RawURLEncoding.Decode(decoded, jwt[:firstDot])
+		headerN = len(cached.raw)
+	}
+
+	claimsN, err := base64.RawURLEncoding.Decode(decoded[headerN:], encodedClaims)
 	if err != nil {
-		return nil, errors.New("failed to decode header from base64url: " + err.Error())
+		return newTokenError(KindMalformed, SegmentClaims, err)
 	}
 
-	claimsN, err := base64.RawURLEncoding.Decode(decoded[headerN:], jwt[firstDot+1:lastDot])
+	signatureN, err := base64.RawURLEncoding.Decode(decoded[headerN+claimsN:], encodedSignature)
 	if err != nil {
-		return nil, errors.New("failed to decode claims from base64url: " + err.Error())
+		return newTokenError(KindMalformed, SegmentSignature, err)
+	}
+
+	claims := decoded[headerN : headerN+claimsN]
+
+	if p.strict {
+		err = checkDuplicateKeys(claims)
+		if err != nil {
+			return newTokenError(KindMalformed, SegmentClaims, err)
+		}
+	}
+
+	if !found && p.headers != nil {
+		p.headers.put(encodedHeader, cached.header, cached.raw)
 	}
 
-	signatureN, err := base64.RawURLEncoding.Decode(decoded[headerN+claimsN:], jwt[lastDot+1:])
+	dst.raw = jwt
+	dst.header = cached.header
+	dst.rawHeader = cached.raw
+	dst.claims = claims
+	dst.payload = jwt[:lastDot]
+	dst.signature = decoded[headerN+claimsN : headerN+claimsN+signatureN]
+
+	return nil
+}
+
+// decodeHeader decodes header segment into buf.
+func (p *JSONParser) decodeHeader(buf, encoded []byte) (cachedHeader, error) {
+	n, err := base64.RawURLEncoding.Decode(buf, encoded)
 	if err != nil {
-		return nil, errors.New("failed to decode signature from base64url: " + err.Error())
+		return cachedHeader{}, newTokenError(KindMalformed, SegmentHeader, err)
 	}
 
 	var h Header
-	err = json.Unmarshal(decoded[:headerN], &h)
+
+	err = json.Unmarshal(buf[:n], &h)
 	if err != nil {
-		return nil, errors.New("failed to unmarshal header: " + err.Error())
+		return cachedHeader{}, newTokenError(KindMalformed, SegmentHeader, err)
 	}
 
-	t := &DecodedParts{
-		raw:       jwt,
-		header:    h,
-		claims:    decoded[headerN : headerN+claimsN],
-		payload:   jwt[:lastDot],
-		signature: decoded[headerN+claimsN : headerN+claimsN+signatureN],
+	if p.strict {
+		err = p.checkStrictHeader(h, buf[:n])
+		if err != nil {
+			return cachedHeader{}, err
+		}
 	}
 
-	return t, nil
+	return cachedHeader{h, buf[:n]}, nil
+}
+
+// checkStrictHeader checks decoded header with strict mode rules.
+func (p *JSONParser) checkStrictHeader(h Header, header []byte) error {
+	err := checkDuplicateKeys(header)
+	if err != nil {
+		return newTokenError(KindMalformed, SegmentHeader, err)
+	}
+
+	if h.Crit == nil {
+		return nil
+	}
+
+	// @see https://tools.ietf.org/html/rfc7515#section-4.1.11
+	if len(h.Crit) == 0 {
+		return newTokenError(KindUnverifiable, SegmentHeader, ErrUnsupportedCritical)
+	}
+
+	var params map[string]json.RawMessage
+
+	err = json.Unmarshal(header, &params)
+	if err != nil {
+		return newTokenError(KindMalformed, SegmentHeader, err)
+	}
+
+	for _, name := range h.Crit {
+		if !p.critical[name] {
+			return newTokenError(KindUnverifiable, SegmentHeader, ErrUnsupportedCritical)
+		}
+
+		if _, ok := params[name]; !ok {
+			return newTokenError(KindUnverifiable, SegmentHeader, ErrUnsupportedCritical)
+		}
+	}
+
+	return nil
+}
+
+// checkDuplicateKeys returns ErrDuplicateKey if any JSON object in data has duplicate keys.
+func checkDuplicateKeys(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	err := checkValueKeys(dec)
+	if err == io.EOF {
+		return errors.New("unexpected end of JSON input")
+	}
+
+	return err
+}
+
+func checkValueKeys(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		keys := make(map[string]struct{})
+
+		for dec.More() {
+			tok, err = dec.Token()
+			if err != nil {
+				return err
+			}
+
+			key, _ := tok.(string)
+			if _, ok := keys[key]; ok {
+				return ErrDuplicateKey
+			}
+
+			keys[key] = struct{}{}
+
+			err = checkValueKeys(dec)
+			if err != nil {
+				return err
+			}
+		}
+	case '[':
+		for dec.More() {
+			err = checkValueKeys(dec)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Consume closing delimiter.
+	_, err = dec.Token()
+
+	return err
 }
 
 // VerifyingParser used to parse and then verify JWT.
 type VerifyingParser struct {
 	Parser
 
-	verifier *PartsVerifier
+	verifier Verifier
 }
 
 // NewVerifyingParser returns new instance of VerifyingParser.
-func NewVerifyingParser(parser Parser, verifier *PartsVerifier) *VerifyingParser {
+func NewVerifyingParser(parser Parser, verifier Verifier) *VerifyingParser {
 	return &VerifyingParser{parser, verifier}
 }
 
 // Parse splits, decode, memoize and verify signature of JWT parts.
-// If token signature is invalid then ErrInvalidSignature returns.
+// Returned errors are *TokenError wrapping the cause, so compare them with errors.Is
+// instead of ==, e.g. errors.Is(err, ErrInvalidSignature) if token signature is invalid
+// or errors.Is(err, ErrAlgorithmMismatch) if token algorithm differs from the verifier's one.
 func (p *VerifyingParser) Parse(jwt json.RawMessage) (*DecodedParts, error) {
 	parts, err := p.Parser.Parse(jwt)
 	if err != nil {
-		return nil, err
+		return nil, newTokenError(KindMalformed, SegmentToken, err)
 	}
 
 	err = p.verifier.Verify(parts)
 	if err != nil {
-		return nil, err
+		return nil, newTokenError(KindUnverifiable, SegmentToken, err)
 	}
 
 	return parts, nil
@@ -1,16 +1,32 @@
 package jwtee
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrAlgorithmMismatch indicates that token's alg header differs from the expected algorithm.
+	ErrAlgorithmMismatch = errors.New("token algorithm does not match the expected algorithm")
+
+	// ErrUnsecuredToken indicates that unsecured token ("alg":"none") is not allowed.
+	ErrUnsecuredToken = errors.New("unsecured token is not allowed")
 )
 
 // DecodedParts stores ready to use parts of the JWT token.
 type DecodedParts struct {
 	raw       []byte
 	header    Header
+	rawHeader []byte
 	claims    json.RawMessage
 	payload   []byte
 	signature []byte
+
+	// buf stores decoded segments, reused by JSONParser.ParseInto.
+	buf []byte
 }
 
 // Header returns token's Header.
@@ -18,6 +34,44 @@ func (t *DecodedParts) Header() Header {
 	return t.header
 }
 
+// RawHeader returns bytes with decoded header JSON, including parameters missing in Header.
+func (t *DecodedParts) RawHeader() []byte {
+	if t.rawHeader != nil {
+		return t.rawHeader
+	}
+
+	dot := bytes.IndexByte(t.payload, sep)
+	if dot < 0 {
+		return nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(string(t.payload[:dot]))
+	if err != nil {
+		return nil
+	}
+
+	return raw
+}
+
+// ExtensionParams returns header parameters which are not fields of Header,
+// e.g. "b64", "nonce", "url" or application specific ones.
+func (t *DecodedParts) ExtensionParams() (map[string]json.RawMessage, error) {
+	params := make(map[string]json.RawMessage)
+
+	err := json.Unmarshal(t.RawHeader(), &params)
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range params {
+		if registeredHeaderParams[name] {
+			delete(params, name)
+		}
+	}
+
+	return params, nil
+}
+
 // RawClaims returns bytes with decoded claims string.
 func (t *DecodedParts) RawClaims() []byte {
 	return t.claims
@@ -45,18 +99,58 @@ func (t *DecodedParts) MarshalText() (text []byte, err error) {
 	return t.MarshalBinary()
 }
 
+// Verifier used to verify signature of JWT.
+type Verifier interface {
+	Verify(parts *DecodedParts) error
+}
+
 // PartsVerifier used to verify signature of JWT.
+// Token's alg header is pinned to the signer algorithm.
 type PartsVerifier struct {
-	signer Signer
-	key    Key
+	signer    Signer
+	key       Key
+	unsecured bool
 }
 
 // NewPartsVerifier returns new instance of PartsVerifier.
 func NewPartsVerifier(signer Signer, key Key) *PartsVerifier {
-	return &PartsVerifier{signer, key}
+	return &PartsVerifier{
+		signer: signer,
+		key:    key,
+	}
+}
+
+// AllowUnsecured enables unsecured mode, in which tokens with "alg":"none" are accepted
+// if verifier's signer is "none" too. Never enable it for untrusted tokens.
+func (v *PartsVerifier) AllowUnsecured() *PartsVerifier {
+	v.unsecured = true
+
+	return v
 }
 
 // Verify used to check consistency of token signature.
+// If token's alg header does not match the signer algorithm then ErrAlgorithmMismatch returns.
+// If token is unsecured and unsecured mode is not enabled then ErrUnsecuredToken returns.
+// Returned errors are *TokenError wrapping the cause.
 func (v *PartsVerifier) Verify(parts *DecodedParts) error {
-	return v.signer.Verify(parts.Signature(), parts.Payload(), v.key)
+	alg := v.signer.GetAlgorithmID()
+
+	if (alg == None || parts.Header().Alg == None) && !v.unsecured {
+		return newTokenError(KindUnverifiable, SegmentHeader, ErrUnsecuredToken)
+	}
+
+	if parts.Header().Alg != alg {
+		return newTokenError(KindUnverifiable, SegmentHeader, ErrAlgorithmMismatch)
+	}
+
+	err := v.signer.Verify(parts.Signature(), parts.Payload(), v.key)
+	if err == ErrInvalidSignature {
+		return newTokenError(KindSignatureInvalid, SegmentSignature, err)
+	}
+
+	if err != nil {
+		return newTokenError(KindUnverifiable, SegmentSignature, err)
+	}
+
+	return nil
 }
@@ -0,0 +1,93 @@
+package jwtee
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Stage describes step of token processing.
+type Stage string
+
+// Stage constants represents steps of token processing in order of execution.
+const (
+	StageParse     Stage = "parse"
+	StageVerify    Stage = "verify"
+	StageUnmarshal Stage = "unmarshal"
+	StageValidate  Stage = "validate"
+)
+
+// ProcessingError describes failed stage of token processing.
+type ProcessingError struct {
+	// Stage is the step at which processing failed.
+	Stage Stage
+
+	// Errs stores failures of the stage.
+	// It holds all constraint errors for StageValidate and exactly one error otherwise.
+	Errs []error
+}
+
+// Error implements error.
+func (e *ProcessingError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+
+	return "failed to " + string(e.Stage) + " token: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns all failures of the stage, so errors.Is and errors.As match any of them.
+func (e *ProcessingError) Unwrap() []error {
+	return e.Errs
+}
+
+// Processor used to parse, verify, unmarshal and validate JWT in a single call.
+type Processor struct {
+	parser    Parser
+	verifier  Verifier
+	validator *ClaimsValidator
+}
+
+// NewProcessor returns new instance of Processor, parsing tokens with JSONParser.
+func NewProcessor(verifier Verifier) *Processor {
+	return &Processor{
+		parser:    NewJSONParser(),
+		verifier:  verifier,
+		validator: NewClaimsValidator(),
+	}
+}
+
+// WithParser sets parser used to take token apart.
+func (p *Processor) WithParser(parser Parser) *Processor {
+	p.parser = parser
+
+	return p
+}
+
+// Process parses and verifies token, unmarshals its claims into claims
+// and validates them with Constraints.
+// The claims must be a pointer to struct embedding RegisteredClaims.
+// On failure *ProcessingError returns.
+func (p *Processor) Process(jwt json.RawMessage, claims Claims, constraints ...Constraint) (*DecodedParts, error) {
+	parts, err := p.parser.Parse(jwt)
+	if err != nil {
+		return nil, &ProcessingError{StageParse, []error{newTokenError(KindMalformed, SegmentToken, err)}}
+	}
+
+	err = p.verifier.Verify(parts)
+	if err != nil {
+		return nil, &ProcessingError{StageVerify, []error{newTokenError(KindUnverifiable, SegmentToken, err)}}
+	}
+
+	err = json.Unmarshal(parts.RawClaims(), claims)
+	if err != nil {
+		return nil, &ProcessingError{StageUnmarshal, []error{newTokenError(KindMalformed, SegmentClaims, err)}}
+	}
+
+	errs := p.validator.validate(claims.Registered(), parts.RawClaims(), constraints)
+	if errs != nil {
+		return nil, &ProcessingError{StageValidate, errs}
+	}
+
+	return parts, nil
+}
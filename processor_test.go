@@ -0,0 +1,122 @@
+package jwtee_test
+
+import (
+	"encoding"
+	"errors"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/constraint"
+	"github.com/furdarius/jwtee/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+type rawclaims []byte
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c rawclaims) MarshalBinary() (data []byte, err error) {
+	return c, nil
+}
+
+func TestProcessor_Process(t *testing.T) {
+	key := jwtee.NewSharedSecretKey([]byte(`secret`))
+	otherKey := jwtee.NewSharedSecretKey([]byte(`other-secret`))
+
+	build := func(claims encoding.BinaryMarshaler) []byte {
+		parts, err := jwtee.NewTokenBuilder().Build(claims, signer.NewHS256(), key)
+		if err != nil {
+			t.Fatalf("failed to build token: %v", err)
+		}
+
+		raw, _ := parts.MarshalText()
+
+		return raw
+	}
+
+	token := build(testclaims{RegisteredClaims: jwtee.RegisteredClaims{Sub: "myservice"}, Name: "John Doe"})
+
+	tests := []struct {
+		desc        string
+		jwt         []byte
+		verifier    jwtee.Verifier
+		constraints []jwtee.Constraint
+		checker     func(t *testing.T, claims testclaims, err error)
+	}{
+		{
+			desc:        "successful processing",
+			jwt:         token,
+			verifier:    jwtee.NewPartsVerifier(signer.NewHS256(), key),
+			constraints: []jwtee.Constraint{constraint.NewRelatedTo("myservice")},
+			checker: func(t *testing.T, claims testclaims, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "John Doe", claims.Name)
+				assert.Equal(t, "myservice", claims.Sub)
+			},
+		},
+		{
+			desc:     "failed on parse stage",
+			jwt:      []byte(`malformed`),
+			verifier: jwtee.NewPartsVerifier(signer.NewHS256(), key),
+			checker: func(t *testing.T, claims testclaims, err error) {
+				var perr *jwtee.ProcessingError
+				assert.True(t, errors.As(err, &perr))
+				assert.Equal(t, jwtee.StageParse, perr.Stage)
+				assert.True(t, errors.Is(err, jwtee.ErrPartMissed))
+			},
+		},
+		{
+			desc:     "failed on verify stage",
+			jwt:      token,
+			verifier: jwtee.NewPartsVerifier(signer.NewHS256(), otherKey),
+			checker: func(t *testing.T, claims testclaims, err error) {
+				var perr *jwtee.ProcessingError
+				assert.True(t, errors.As(err, &perr))
+				assert.Equal(t, jwtee.StageVerify, perr.Stage)
+				assert.True(t, errors.Is(err, jwtee.ErrInvalidSignature))
+				assert.Empty(t, claims.Name)
+			},
+		},
+		{
+			desc:     "failed on unmarshal stage",
+			jwt:      build(rawclaims(`{"sub":42}`)),
+			verifier: jwtee.NewPartsVerifier(signer.NewHS256(), key),
+			checker: func(t *testing.T, claims testclaims, err error) {
+				var perr *jwtee.ProcessingError
+				assert.True(t, errors.As(err, &perr))
+				assert.Equal(t, jwtee.StageUnmarshal, perr.Stage)
+			},
+		},
+		{
+			desc:     "failed on validate stage with all constraint errors",
+			jwt:      token,
+			verifier: jwtee.NewPartsVerifier(signer.NewHS256(), key),
+			constraints: []jwtee.Constraint{
+				constraint.NewRelatedTo("otherservice"),
+				constraint.NewHasScope("read"),
+			},
+			checker: func(t *testing.T, claims testclaims, err error) {
+				var perr *jwtee.ProcessingError
+				assert.True(t, errors.As(err, &perr))
+				assert.Equal(t, jwtee.StageValidate, perr.Stage)
+				assert.Len(t, perr.Errs, 2)
+				assert.True(t, errors.Is(perr.Errs[0], constraint.ErrTokenInvalidRelation))
+				assert.True(t, errors.Is(perr.Errs[1], constraint.ErrTokenInsufficientScope))
+				assert.True(t, errors.Is(err, constraint.ErrTokenInvalidRelation))
+				assert.True(t, errors.Is(err, constraint.ErrTokenInsufficientScope))
+				assert.True(t, errors.Is(err, jwtee.ErrKindClaimsInvalid))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			var claims testclaims
+
+			_, err := jwtee.NewProcessor(tt.verifier).Process(tt.jwt, &claims, tt.constraints...)
+
+			tt.checker(t, claims, err)
+		})
+	}
+}
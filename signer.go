@@ -8,24 +8,10 @@ var (
 
 	// ErrRequestedHashUnavailable indicates that hash func is not registered.
 	ErrRequestedHashUnavailable = errors.New("requested hash function is unavailable")
-)
-
-// Key stores signing key data.
-type Key struct {
-	secret []byte
-}
-
-// NewSharedSecretKey returns Key with secret inside.
-func NewSharedSecretKey(secret []byte) Key {
-	return Key{
-		secret: secret,
-	}
-}
 
-// Secret returns key's secret.
-func (k Key) Secret() []byte {
-	return k.secret
-}
+	// ErrInvalidKey indicates that key material is not suitable for the algorithm.
+	ErrInvalidKey = errors.New("key is invalid for the algorithm")
+)
 
 // Signer used to sign and verify token signature.
 type Signer interface {
@@ -33,3 +19,9 @@ type Signer interface {
 	Sign(payload []byte, key Key) ([]byte, error)
 	Verify(expected, payload []byte, key Key) error
 }
+
+// SignatureSizer is implemented by Signers which know signature length before signing.
+// It lets builders allocate token buffer once.
+type SignatureSizer interface {
+	SignatureSize(key Key) int
+}
@@ -0,0 +1,128 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	_ "crypto/sha256" // link binary
+	_ "crypto/sha512" // link binary
+	"encoding/asn1"
+	"errors"
+	"math/big"
+
+	"github.com/furdarius/jwtee"
+)
+
+// Block represents ECDSA signer errors.
+var (
+	ErrCurveMismatch = errors.New("key curve does not match the algorithm")
+)
+
+// ECDSA implements Signer with ECDSA.
+// Signature is encoded as fixed-length R || S octet sequence.
+// @see https://tools.ietf.org/html/rfc7518#section-3.4
+type ECDSA struct {
+	alg   jwtee.Algorithm
+	hash  crypto.Hash
+	curve elliptic.Curve
+	// size is length of R and S in bytes.
+	size int
+}
+
+// NewES256 returns new ECDSA Signer using P-256 and SHA256.
+func NewES256() *ECDSA {
+	return &ECDSA{jwtee.ES256, crypto.SHA256, elliptic.P256(), 32}
+}
+
+// NewES384 returns new ECDSA Signer using P-384 and SHA384.
+func NewES384() *ECDSA {
+	return &ECDSA{jwtee.ES384, crypto.SHA384, elliptic.P384(), 48}
+}
+
+// NewES512 returns new ECDSA Signer using P-521 and SHA512.
+func NewES512() *ECDSA {
+	return &ECDSA{jwtee.ES512, crypto.SHA512, elliptic.P521(), 66}
+}
+
+// GetAlgorithmID inherited from Signer.
+func (e *ECDSA) GetAlgorithmID() jwtee.Algorithm {
+	return e.alg
+}
+
+// SignatureSize inherited from SignatureSizer.
+func (e *ECDSA) SignatureSize(key jwtee.Key) int {
+	return 2 * e.size
+}
+
+// Sign inherited from Signer.
+func (e *ECDSA) Sign(payload []byte, key jwtee.Key) ([]byte, error) {
+	private := key.PrivateKey()
+	if private == nil || !isIntendedFor(key, e.alg) {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	public, ok := private.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	if public.Curve != e.curve {
+		return nil, ErrCurveMismatch
+	}
+
+	digest, err := hashPayload(e.hash, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := private.Sign(rand.Reader, digest, e.hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var sig struct {
+		R, S *big.Int
+	}
+
+	_, err = asn1.Unmarshal(der, &sig)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := make([]byte, 2*e.size)
+	sig.R.FillBytes(signature[:e.size])
+	sig.S.FillBytes(signature[e.size:])
+
+	return signature, nil
+}
+
+// Verify inherited from Signer.
+func (e *ECDSA) Verify(expected, payload []byte, key jwtee.Key) error {
+	public, ok := key.PublicKey().(*ecdsa.PublicKey)
+	if !ok || !isIntendedFor(key, e.alg) {
+		return jwtee.ErrInvalidKey
+	}
+
+	if public.Curve != e.curve {
+		return ErrCurveMismatch
+	}
+
+	if len(expected) != 2*e.size {
+		return jwtee.ErrInvalidSignature
+	}
+
+	digest, err := hashPayload(e.hash, payload)
+	if err != nil {
+		return err
+	}
+
+	r := new(big.Int).SetBytes(expected[:e.size])
+	s := new(big.Int).SetBytes(expected[e.size:])
+
+	if !ecdsa.Verify(public, digest, r, s) {
+		return jwtee.ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package signer_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateECDSAKey(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	private, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+
+	return private
+}
+
+func TestECDSA_Verify(t *testing.T) {
+	private := generateECDSAKey(t, elliptic.P256())
+	payload := []byte(`eyJhbGciOiJFUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxMjM0NTY3ODkwIn0`)
+
+	signature, err := signer.NewES256().Sign(payload, jwtee.NewECDSAPrivateKey(private))
+	if err != nil {
+		t.Fatalf("failed to sign payload: %v", err)
+	}
+
+	r, s, err := ecdsa.Sign(rand.Reader, private, make([]byte, 32))
+	if err != nil {
+		t.Fatalf("failed to sign digest: %v", err)
+	}
+
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Fatalf("failed to marshal signature: %v", err)
+	}
+
+	tests := []struct {
+		desc      string
+		payload   []byte
+		signature []byte
+		key       jwtee.Key
+		signer    jwtee.Signer
+		checker   func(t *testing.T, err error)
+	}{
+		{
+			desc:      "successful verifying ES256",
+			payload:   payload,
+			signature: signature,
+			key:       jwtee.NewECDSAPublicKey(&private.PublicKey),
+			signer:    signer.NewES256(),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:      "failed verifying modified payload",
+			payload:   append(append([]byte{}, payload...), 'x'),
+			signature: signature,
+			key:       jwtee.NewECDSAPublicKey(&private.PublicKey),
+			signer:    signer.NewES256(),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidSignature, err)
+			},
+		},
+		{
+			desc:      "failed verifying truncated signature",
+			payload:   payload,
+			signature: signature[:63],
+			key:       jwtee.NewECDSAPublicKey(&private.PublicKey),
+			signer:    signer.NewES256(),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidSignature, err)
+			},
+		},
+		{
+			desc:      "failed verifying ASN.1 DER signature",
+			payload:   payload,
+			signature: der,
+			key:       jwtee.NewECDSAPublicKey(&private.PublicKey),
+			signer:    signer.NewES256(),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidSignature, err)
+			},
+		},
+		{
+			desc:      "failed verifying with curve mismatch",
+			payload:   payload,
+			signature: signature,
+			key:       jwtee.NewECDSAPublicKey(&private.PublicKey),
+			signer:    signer.NewES384(),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, signer.ErrCurveMismatch, err)
+			},
+		},
+		{
+			desc:      "failed verifying with RSA algorithm",
+			payload:   payload,
+			signature: signature,
+			key:       jwtee.NewECDSAPublicKey(&private.PublicKey),
+			signer:    signer.NewRS256(),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidKey, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			err := test.signer.Verify(test.signature, test.payload, test.key)
+			test.checker(t, err)
+		})
+	}
+}
+
+func TestECDSA_Sign(t *testing.T) {
+	tests := []struct {
+		desc    string
+		curve   elliptic.Curve
+		signer  *signer.ECDSA
+		size    int
+		checker func(t *testing.T, err error)
+	}{
+		{
+			desc:   "successful signing ES256",
+			curve:  elliptic.P256(),
+			signer: signer.NewES256(),
+			size:   64,
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:   "successful signing ES384",
+			curve:  elliptic.P384(),
+			signer: signer.NewES384(),
+			size:   96,
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:   "successful signing ES512",
+			curve:  elliptic.P521(),
+			signer: signer.NewES512(),
+			size:   132,
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			private := generateECDSAKey(t, test.curve)
+			payload := []byte(`eyJhbGciOiJFUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxMjM0NTY3ODkwIn0`)
+
+			signature, err := test.signer.Sign(payload, jwtee.NewECDSAPrivateKey(private))
+			assert.NoError(t, err)
+			assert.Len(t, signature, test.size)
+
+			err = test.signer.Verify(signature, payload, jwtee.NewECDSAPublicKey(&private.PublicKey))
+			test.checker(t, err)
+		})
+	}
+}
+
+func TestECDSA_SignWithCurveMismatch(t *testing.T) {
+	private := generateECDSAKey(t, elliptic.P384())
+
+	_, err := signer.NewES256().Sign([]byte(`payload`), jwtee.NewECDSAPrivateKey(private))
+	assert.Equal(t, signer.ErrCurveMismatch, err)
+}
+
+func TestECDSA_BuildAndVerify(t *testing.T) {
+	private := generateECDSAKey(t, elliptic.P256())
+	ecdsaSigner := signer.NewES256()
+
+	parts, err := jwtee.NewTokenBuilder().Build(testclaims{Name: "John Doe"}, ecdsaSigner, jwtee.NewECDSAPrivateKey(private))
+	assert.NoError(t, err)
+
+	raw, err := parts.MarshalText()
+	assert.NoError(t, err)
+
+	verifier := jwtee.NewPartsVerifier(ecdsaSigner, jwtee.NewECDSAPublicKey(&private.PublicKey))
+	parsed, err := jwtee.NewVerifyingParser(jwtee.NewJSONParser(), verifier).Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, jwtee.ES256, parsed.Header().Alg)
+}
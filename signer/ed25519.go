@@ -0,0 +1,57 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+
+	"github.com/furdarius/jwtee"
+)
+
+// Ed25519 implements Signer with EdDSA over Ed25519 curve.
+// @see https://tools.ietf.org/html/rfc8037#section-3.1
+type Ed25519 struct{}
+
+// NewEdDSA returns new Ed25519 Signer.
+func NewEdDSA() *Ed25519 {
+	return &Ed25519{}
+}
+
+// GetAlgorithmID inherited from Signer.
+func (e *Ed25519) GetAlgorithmID() jwtee.Algorithm {
+	return jwtee.EdDSA
+}
+
+// SignatureSize inherited from SignatureSizer.
+func (e *Ed25519) SignatureSize(key jwtee.Key) int {
+	return ed25519.SignatureSize
+}
+
+// Sign inherited from Signer.
+func (e *Ed25519) Sign(payload []byte, key jwtee.Key) ([]byte, error) {
+	private := key.PrivateKey()
+	if private == nil || !isIntendedFor(key, jwtee.EdDSA) {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	if _, ok := private.Public().(ed25519.PublicKey); !ok {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	// Ed25519 signs the whole message, so hash func must be zero.
+	return private.Sign(rand.Reader, payload, crypto.Hash(0))
+}
+
+// Verify inherited from Signer.
+func (e *Ed25519) Verify(expected, payload []byte, key jwtee.Key) error {
+	public, ok := key.PublicKey().(ed25519.PublicKey)
+	if !ok || len(public) != ed25519.PublicKeySize || !isIntendedFor(key, jwtee.EdDSA) {
+		return jwtee.ErrInvalidKey
+	}
+
+	if !ed25519.Verify(public, payload, expected) {
+		return jwtee.ErrInvalidSignature
+	}
+
+	return nil
+}
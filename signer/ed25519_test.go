@@ -0,0 +1,116 @@
+package signer_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Example from https://tools.ietf.org/html/rfc8037#appendix-A.4
+const (
+	ed25519Seed      = `nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A`
+	ed25519Payload   = `eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc`
+	ed25519Signature = `hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg`
+)
+
+func ed25519Key(t *testing.T) ed25519.PrivateKey {
+	seed, err := base64.RawURLEncoding.DecodeString(ed25519Seed)
+	if err != nil {
+		t.Fatalf("failed to decode seed: %v", err)
+	}
+
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+func TestEd25519_Sign(t *testing.T) {
+	private := ed25519Key(t)
+
+	signature, err := signer.NewEdDSA().Sign([]byte(ed25519Payload), jwtee.NewEd25519PrivateKey(private))
+	assert.NoError(t, err)
+
+	encoded := make([]byte, base64.RawURLEncoding.EncodedLen(len(signature)))
+	base64.RawURLEncoding.Encode(encoded, signature)
+
+	assert.Equal(t, []byte(ed25519Signature), encoded)
+}
+
+func TestEd25519_Verify(t *testing.T) {
+	private := ed25519Key(t)
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	tests := []struct {
+		desc    string
+		payload []byte
+		key     jwtee.Key
+		checker func(t *testing.T, err error)
+	}{
+		{
+			desc:    "successful verifying EdDSA",
+			payload: []byte(ed25519Payload),
+			key:     jwtee.NewEd25519PublicKey(private.Public().(ed25519.PublicKey)),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:    "failed verifying modified payload",
+			payload: []byte(ed25519Payload + "x"),
+			key:     jwtee.NewEd25519PublicKey(private.Public().(ed25519.PublicKey)),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidSignature, err)
+			},
+		},
+		{
+			desc:    "failed verifying with another key",
+			payload: []byte(ed25519Payload),
+			key:     jwtee.NewEd25519PrivateKey(other),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidSignature, err)
+			},
+		},
+		{
+			desc:    "failed verifying with shared secret",
+			payload: []byte(ed25519Payload),
+			key:     jwtee.NewSharedSecretKey([]byte(`1234`)),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidKey, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			decoded, err := base64.RawURLEncoding.DecodeString(ed25519Signature)
+			if err != nil {
+				panic("failed to decode signature from base64")
+			}
+
+			err = signer.NewEdDSA().Verify(decoded, test.payload, test.key)
+			test.checker(t, err)
+		})
+	}
+}
+
+func TestEd25519_BuildAndVerify(t *testing.T) {
+	private := ed25519Key(t)
+	edSigner := signer.NewEdDSA()
+
+	parts, err := jwtee.NewTokenBuilder().Build(testclaims{Name: "John Doe"}, edSigner, jwtee.NewEd25519PrivateKey(private))
+	assert.NoError(t, err)
+
+	raw, err := parts.MarshalText()
+	assert.NoError(t, err)
+
+	verifier := jwtee.NewPartsVerifier(edSigner, jwtee.NewEd25519PublicKey(private.Public().(ed25519.PublicKey)))
+	parsed, err := jwtee.NewVerifyingParser(jwtee.NewJSONParser(), verifier).Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, jwtee.EdDSA, parsed.Header().Alg)
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/hmac"
 	_ "crypto/sha256" // link binary
 	_ "crypto/sha512" // link binary
+	"hash"
+	"sync"
 
 	"github.com/furdarius/jwtee"
 )
@@ -13,21 +15,64 @@ import (
 type HMAC struct {
 	alg  jwtee.Algorithm
 	hash crypto.Hash
+
+	// secret is the bound key, nil if HMAC is not bound.
+	secret []byte
+
+	// pool stores *keyedHash instances for the bound key.
+	pool *sync.Pool
+}
+
+// keyedHash stores HMAC state with precomputed key pads and buffer for the sum.
+type keyedHash struct {
+	hash.Hash
+
+	sum []byte
 }
 
 // NewHS256 returns new HMAC Signer using SHA256.
 func NewHS256() *HMAC {
-	return &HMAC{jwtee.HS256, crypto.SHA256}
+	return &HMAC{alg: jwtee.HS256, hash: crypto.SHA256}
 }
 
 // NewHS384 returns new HMAC Signer using SHA384.
 func NewHS384() *HMAC {
-	return &HMAC{jwtee.HS384, crypto.SHA384}
+	return &HMAC{alg: jwtee.HS384, hash: crypto.SHA384}
 }
 
 // NewHS512 returns new HMAC Signer using HS512.
 func NewHS512() *HMAC {
-	return &HMAC{jwtee.HS512, crypto.SHA512}
+	return &HMAC{alg: jwtee.HS512, hash: crypto.SHA512}
+}
+
+// Bind returns new HMAC Signer bound to the key.
+// Keyed hash state is computed once and pooled, so bound Signer is safe for concurrent use.
+// Signing and verifying with another key falls back to computing keyed state per call.
+func (h *HMAC) Bind(key jwtee.Key) (*HMAC, error) {
+	if !h.hash.Available() {
+		return nil, jwtee.ErrRequestedHashUnavailable
+	}
+
+	if !isValidSecret(key, h.alg) {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	// Copy the secret, so later changes of caller's key do not affect pooled keyed states.
+	secret := append([]byte(nil), key.Secret()...)
+	newHash := h.hash.New
+
+	return &HMAC{
+		alg:    h.alg,
+		hash:   h.hash,
+		secret: secret,
+		pool: &sync.Pool{
+			New: func() interface{} {
+				mac := hmac.New(newHash, secret)
+
+				return &keyedHash{mac, make([]byte, 0, mac.Size())}
+			},
+		},
+	}, nil
 }
 
 // GetAlgorithmID inherited from Signer.
@@ -35,34 +80,95 @@ func (h *HMAC) GetAlgorithmID() jwtee.Algorithm {
 	return h.alg
 }
 
+// SignatureSize inherited from SignatureSizer.
+func (h *HMAC) SignatureSize(key jwtee.Key) int {
+	return h.hash.Size()
+}
+
 // Sign inherited from Signer.
 func (h *HMAC) Sign(payload []byte, key jwtee.Key) ([]byte, error) {
-	if !h.hash.Available() {
-		return nil, jwtee.ErrRequestedHashUnavailable
-	}
+	if h.isBoundTo(key) {
+		mac := h.pool.Get().(*keyedHash)
+		mac.Reset()
+		mac.Write(payload)
 
-	digest := hmac.New(h.hash.New, key.Secret())
+		signed := mac.Sum(make([]byte, 0, mac.Size()))
 
-	_, err := digest.Write(payload)
+		h.pool.Put(mac)
+
+		return signed, nil
+	}
+
+	digest, err := h.newDigest(payload, key)
 	if err != nil {
 		return nil, err
 	}
 
-	signed := digest.Sum(nil)
-
-	return signed, nil
+	return digest.Sum(nil), nil
 }
 
 // Verify inherited from Signer.
 func (h *HMAC) Verify(expected, payload []byte, key jwtee.Key) error {
-	signed, err := h.Sign(payload, key)
+	if h.isBoundTo(key) {
+		mac := h.pool.Get().(*keyedHash)
+		mac.Reset()
+		mac.Write(payload)
+
+		mac.sum = mac.Sum(mac.sum[:0])
+		valid := hmac.Equal(expected, mac.sum)
+
+		h.pool.Put(mac)
+
+		if !valid {
+			return jwtee.ErrInvalidSignature
+		}
+
+		return nil
+	}
+
+	digest, err := h.newDigest(payload, key)
 	if err != nil {
 		return err
 	}
 
-	if !hmac.Equal(expected, signed) {
+	if !hmac.Equal(expected, digest.Sum(nil)) {
 		return jwtee.ErrInvalidSignature
 	}
 
 	return nil
 }
+
+// newDigest returns HMAC state keyed with the key, which has consumed payload.
+func (h *HMAC) newDigest(payload []byte, key jwtee.Key) (hash.Hash, error) {
+	if !h.hash.Available() {
+		return nil, jwtee.ErrRequestedHashUnavailable
+	}
+
+	if !isValidSecret(key, h.alg) {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	digest := hmac.New(h.hash.New, key.Secret())
+
+	_, err := digest.Write(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return digest, nil
+}
+
+// isBoundTo returns true if HMAC is bound to the key.
+func (h *HMAC) isBoundTo(key jwtee.Key) bool {
+	if h.pool == nil || !isValidSecret(key, h.alg) {
+		return false
+	}
+
+	return hmac.Equal(key.Secret(), h.secret)
+}
+
+// isValidSecret returns true if key is non-empty shared secret intended for the algorithm.
+// Asymmetric keys carry no secret, so accepting them would allow to forge signatures with empty secret.
+func isValidSecret(key jwtee.Key, alg jwtee.Algorithm) bool {
+	return key.Type() == jwtee.KeyTypeOct && len(key.Secret()) > 0 && isIntendedFor(key, alg)
+}
@@ -1,7 +1,12 @@
 package signer_test
 
 import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base64"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -114,6 +119,16 @@ func TestHMAC_Verify(t *testing.T) {
 				assert.NoError(t, err)
 			},
 		},
+		{
+			desc:      "failed verifying with key intended for another algorithm",
+			payload:   []byte(`eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRHdvZSIsImlhdCI6MTUxNjIzOTAyMn0`),
+			signature: []byte(`JzaK6yp8NxAj8gQ1gPP6xu8wpxQ1q4Pno9Co8_XJjk0`),
+			key:       jwtee.NewSharedSecretKey([]byte(`1234`)).WithAlgorithm(jwtee.HS512),
+			signer:    signer.NewHS256(),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidKey, err)
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -129,3 +144,200 @@ func TestHMAC_Verify(t *testing.T) {
 		})
 	}
 }
+
+func TestHMAC_Bind(t *testing.T) {
+	payload := []byte(`eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRHdvZSIsImlhdCI6MTUxNjIzOTAyMn0`)
+	key := jwtee.NewSharedSecretKey([]byte(`1234`))
+	otherKey := jwtee.NewSharedSecretKey([]byte(`4321`))
+
+	tests := []struct {
+		desc   string
+		signer *signer.HMAC
+	}{
+		{"HS256", signer.NewHS256()},
+		{"HS384", signer.NewHS384()},
+		{"HS512", signer.NewHS512()},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			bound, err := test.signer.Bind(key)
+			assert.NoError(t, err)
+
+			expected, err := test.signer.Sign(payload, key)
+			assert.NoError(t, err)
+
+			signature, err := bound.Sign(payload, key)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, signature)
+
+			// Key with the same secret but another backing array.
+			assert.NoError(t, bound.Verify(expected, payload, jwtee.NewSharedSecretKey([]byte(`1234`))))
+			assert.Equal(t, jwtee.ErrInvalidSignature, bound.Verify(expected[1:], payload, key))
+			assert.Equal(t, jwtee.ErrInvalidSignature, bound.Verify(expected, payload[1:], key))
+
+			otherExpected, err := test.signer.Sign(payload, otherKey)
+			assert.NoError(t, err)
+
+			otherSignature, err := bound.Sign(payload, otherKey)
+			assert.NoError(t, err)
+			assert.Equal(t, otherExpected, otherSignature)
+			assert.Equal(t, jwtee.ErrInvalidSignature, bound.Verify(expected, payload, otherKey))
+		})
+	}
+
+	_, err := signer.NewHS256().Bind(key.WithAlgorithm(jwtee.HS512))
+	assert.Equal(t, jwtee.ErrInvalidKey, err)
+}
+
+func TestHMAC_NonSecretKey(t *testing.T) {
+	payload := []byte(`eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.e30`)
+
+	// Signature computed with empty secret, which non-oct keys would provide.
+	forged, err := signer.NewHS256().Sign(payload, jwtee.NewSharedSecretKey([]byte{}))
+	assert.Equal(t, jwtee.ErrInvalidKey, err)
+	assert.Nil(t, forged)
+
+	rsaKey := generateRSAKey(t)
+	ecKey := generateECDSAKey(t, elliptic.P256())
+
+	tests := []struct {
+		desc string
+		key  jwtee.Key
+	}{
+		{"RSA public key", jwtee.NewRSAPublicKey(&rsaKey.PublicKey)},
+		{"RSA private key", jwtee.NewRSAPrivateKey(rsaKey)},
+		{"EC public key", jwtee.NewECDSAPublicKey(&ecKey.PublicKey)},
+		{"empty secret", jwtee.NewSharedSecretKey(nil)},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			s := signer.NewHS256()
+
+			_, err := s.Sign(payload, test.key)
+			assert.Equal(t, jwtee.ErrInvalidKey, err)
+
+			emptyMAC := hmac.New(sha256.New, nil)
+			emptyMAC.Write(payload)
+			assert.Equal(t, jwtee.ErrInvalidKey, s.Verify(emptyMAC.Sum(nil), payload, test.key))
+
+			_, err = s.Bind(test.key)
+			assert.Equal(t, jwtee.ErrInvalidKey, err)
+
+			bound, err := s.Bind(jwtee.NewSharedSecretKey([]byte(`1234`)))
+			assert.NoError(t, err)
+			assert.Equal(t, jwtee.ErrInvalidKey, bound.Verify(emptyMAC.Sum(nil), payload, test.key))
+		})
+	}
+}
+
+func TestHMAC_Bind_SecretModified(t *testing.T) {
+	payload := []byte(`eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.e30`)
+	secret := []byte(`1234`)
+	key := jwtee.NewSharedSecretKey(secret)
+
+	bound, err := signer.NewHS256().Bind(key)
+	assert.NoError(t, err)
+
+	expected, err := bound.Sign(payload, key)
+	assert.NoError(t, err)
+
+	secret[0] = '5'
+
+	modified, err := signer.NewHS256().Sign(payload, key)
+	assert.NoError(t, err)
+
+	signature, err := bound.Sign(payload, key)
+	assert.NoError(t, err)
+	assert.Equal(t, modified, signature)
+
+	signature, err = bound.Sign(payload, jwtee.NewSharedSecretKey([]byte(`1234`)))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, signature)
+}
+
+func TestHMAC_Bind_Concurrent(t *testing.T) {
+	key := jwtee.NewSharedSecretKey([]byte(`1234`))
+
+	bound, err := signer.NewHS256().Bind(key)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			payload := []byte(strconv.Itoa(g))
+
+			for i := 0; i < 100; i++ {
+				signature, err := bound.Sign(payload, key)
+				assert.NoError(t, err)
+				assert.NoError(t, bound.Verify(signature, payload, key))
+			}
+		}(g)
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkHMAC(b *testing.B) {
+	payload := []byte(`eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRHdvZSIsImlhdCI6MTUxNjIzOTAyMn0`)
+	key := jwtee.NewSharedSecretKey([]byte(`secret`))
+
+	signers := []*signer.HMAC{signer.NewHS256(), signer.NewHS384(), signer.NewHS512()}
+
+	for _, s := range signers {
+		bound, err := s.Bind(key)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		signature, err := s.Sign(payload, key)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		benchmarks := []struct {
+			desc   string
+			signer *signer.HMAC
+		}{
+			{"unbound", s},
+			{"bound", bound},
+		}
+
+		for _, bm := range benchmarks {
+			bm := bm
+			name := string(s.GetAlgorithmID()) + "/" + bm.desc
+
+			b.Run(name+"/sign", func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					_, err := bm.signer.Sign(payload, key)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+
+			b.Run(name+"/verify", func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					err := bm.signer.Verify(signature, payload, key)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package signer
+
+import (
+	"github.com/furdarius/jwtee"
+)
+
+// None implements Signer for unsecured JWT, which has empty signature.
+// Verifier accepts such tokens only in unsecured mode.
+// @see https://tools.ietf.org/html/rfc7519#section-6
+type None struct{}
+
+// NewNone returns new None Signer.
+func NewNone() *None {
+	return &None{}
+}
+
+// GetAlgorithmID inherited from Signer.
+func (n *None) GetAlgorithmID() jwtee.Algorithm {
+	return jwtee.None
+}
+
+// SignatureSize inherited from SignatureSizer.
+func (n *None) SignatureSize(key jwtee.Key) int {
+	return 0
+}
+
+// Sign inherited from Signer.
+func (n *None) Sign(payload []byte, key jwtee.Key) ([]byte, error) {
+	return []byte{}, nil
+}
+
+// Verify inherited from Signer.
+func (n *None) Verify(expected, payload []byte, key jwtee.Key) error {
+	if len(expected) != 0 {
+		return jwtee.ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256" // link binary
+	_ "crypto/sha512" // link binary
+
+	"github.com/furdarius/jwtee"
+)
+
+// RSA implements Signer with RSASSA-PKCS1-v1_5.
+type RSA struct {
+	alg  jwtee.Algorithm
+	hash crypto.Hash
+}
+
+// NewRS256 returns new RSA Signer using SHA256.
+func NewRS256() *RSA {
+	return &RSA{jwtee.RS256, crypto.SHA256}
+}
+
+// NewRS384 returns new RSA Signer using SHA384.
+func NewRS384() *RSA {
+	return &RSA{jwtee.RS384, crypto.SHA384}
+}
+
+// NewRS512 returns new RSA Signer using SHA512.
+func NewRS512() *RSA {
+	return &RSA{jwtee.RS512, crypto.SHA512}
+}
+
+// GetAlgorithmID inherited from Signer.
+func (r *RSA) GetAlgorithmID() jwtee.Algorithm {
+	return r.alg
+}
+
+// SignatureSize inherited from SignatureSizer.
+func (r *RSA) SignatureSize(key jwtee.Key) int {
+	return rsaSignatureSize(key)
+}
+
+// Sign inherited from Signer.
+func (r *RSA) Sign(payload []byte, key jwtee.Key) ([]byte, error) {
+	private := key.PrivateKey()
+	if private == nil || !isIntendedFor(key, r.alg) {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	if _, ok := private.Public().(*rsa.PublicKey); !ok {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	digest, err := hashPayload(r.hash, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return private.Sign(rand.Reader, digest, r.hash)
+}
+
+// Verify inherited from Signer.
+func (r *RSA) Verify(expected, payload []byte, key jwtee.Key) error {
+	public, ok := key.PublicKey().(*rsa.PublicKey)
+	if !ok || !isIntendedFor(key, r.alg) {
+		return jwtee.ErrInvalidKey
+	}
+
+	digest, err := hashPayload(r.hash, payload)
+	if err != nil {
+		return err
+	}
+
+	err = rsa.VerifyPKCS1v15(public, r.hash, digest, expected)
+	if err != nil {
+		return jwtee.ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// isIntendedFor returns true if key is not restricted to another algorithm.
+func isIntendedFor(key jwtee.Key, alg jwtee.Algorithm) bool {
+	return key.Algorithm() == "" || key.Algorithm() == alg
+}
+
+// hashPayload returns digest of payload computed with given hash func.
+func hashPayload(hash crypto.Hash, payload []byte) ([]byte, error) {
+	if !hash.Available() {
+		return nil, jwtee.ErrRequestedHashUnavailable
+	}
+
+	digest := hash.New()
+
+	_, err := digest.Write(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return digest.Sum(nil), nil
+}
+
+// rsaSignatureSize returns modulus size of the RSA key in bytes, or zero for other keys.
+func rsaSignatureSize(key jwtee.Key) int {
+	public, ok := key.PublicKey().(*rsa.PublicKey)
+	if !ok {
+		return 0
+	}
+
+	return public.Size()
+}
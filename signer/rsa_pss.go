@@ -0,0 +1,95 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256" // link binary
+	_ "crypto/sha512" // link binary
+
+	"github.com/furdarius/jwtee"
+)
+
+// RSAPSS implements Signer with RSASSA-PSS.
+// Salt length is equal to the size of the hash function output.
+// @see https://tools.ietf.org/html/rfc7518#section-3.5
+type RSAPSS struct {
+	alg  jwtee.Algorithm
+	hash crypto.Hash
+	opts *rsa.PSSOptions
+}
+
+// NewPS256 returns new RSAPSS Signer using SHA256.
+func NewPS256() *RSAPSS {
+	return newRSAPSS(jwtee.PS256, crypto.SHA256)
+}
+
+// NewPS384 returns new RSAPSS Signer using SHA384.
+func NewPS384() *RSAPSS {
+	return newRSAPSS(jwtee.PS384, crypto.SHA384)
+}
+
+// NewPS512 returns new RSAPSS Signer using SHA512.
+func NewPS512() *RSAPSS {
+	return newRSAPSS(jwtee.PS512, crypto.SHA512)
+}
+
+func newRSAPSS(alg jwtee.Algorithm, hash crypto.Hash) *RSAPSS {
+	return &RSAPSS{
+		alg:  alg,
+		hash: hash,
+		opts: &rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
+			Hash:       hash,
+		},
+	}
+}
+
+// GetAlgorithmID inherited from Signer.
+func (r *RSAPSS) GetAlgorithmID() jwtee.Algorithm {
+	return r.alg
+}
+
+// SignatureSize inherited from SignatureSizer.
+func (r *RSAPSS) SignatureSize(key jwtee.Key) int {
+	return rsaSignatureSize(key)
+}
+
+// Sign inherited from Signer.
+func (r *RSAPSS) Sign(payload []byte, key jwtee.Key) ([]byte, error) {
+	private := key.PrivateKey()
+	if private == nil || !isIntendedFor(key, r.alg) {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	if _, ok := private.Public().(*rsa.PublicKey); !ok {
+		return nil, jwtee.ErrInvalidKey
+	}
+
+	digest, err := hashPayload(r.hash, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return private.Sign(rand.Reader, digest, r.opts)
+}
+
+// Verify inherited from Signer.
+func (r *RSAPSS) Verify(expected, payload []byte, key jwtee.Key) error {
+	public, ok := key.PublicKey().(*rsa.PublicKey)
+	if !ok || !isIntendedFor(key, r.alg) {
+		return jwtee.ErrInvalidKey
+	}
+
+	digest, err := hashPayload(r.hash, payload)
+	if err != nil {
+		return err
+	}
+
+	err = rsa.VerifyPSS(public, r.hash, digest, expected, r.opts)
+	if err != nil {
+		return jwtee.ErrInvalidSignature
+	}
+
+	return nil
+}
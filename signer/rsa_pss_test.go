@@ -0,0 +1,125 @@
+package signer_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRSAPSS_SignVerify(t *testing.T) {
+	private := generateRSAKey(t)
+	payload := []byte(`eyJhbGciOiJQUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRG9lIiwiaWF0IjoxNTE2MjM5MDIyfQ`)
+
+	digest := sha256.Sum256(payload)
+	zeroSalted, err := rsa.SignPSS(rand.Reader, private, crypto.SHA256, digest[:], &rsa.PSSOptions{SaltLength: 0})
+	if err != nil {
+		t.Fatalf("failed to sign payload: %v", err)
+	}
+
+	tests := []struct {
+		desc    string
+		signer  *signer.RSAPSS
+		sign    func(s *signer.RSAPSS) ([]byte, error)
+		key     jwtee.Key
+		checker func(t *testing.T, err error)
+	}{
+		{
+			desc:   "successful verifying PS256",
+			signer: signer.NewPS256(),
+			sign: func(s *signer.RSAPSS) ([]byte, error) {
+				return s.Sign(payload, jwtee.NewRSAPrivateKey(private))
+			},
+			key: jwtee.NewRSAPublicKey(&private.PublicKey),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:   "successful verifying PS384",
+			signer: signer.NewPS384(),
+			sign: func(s *signer.RSAPSS) ([]byte, error) {
+				return s.Sign(payload, jwtee.NewRSAPrivateKey(private))
+			},
+			key: jwtee.NewRSAPublicKey(&private.PublicKey),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:   "successful verifying PS512",
+			signer: signer.NewPS512(),
+			sign: func(s *signer.RSAPSS) ([]byte, error) {
+				return s.Sign(payload, jwtee.NewRSAPrivateKey(private))
+			},
+			key: jwtee.NewRSAPublicKey(&private.PublicKey),
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:   "failed verifying PKCS#1 v1.5 signature",
+			signer: signer.NewPS256(),
+			sign: func(s *signer.RSAPSS) ([]byte, error) {
+				return signer.NewRS256().Sign(payload, jwtee.NewRSAPrivateKey(private))
+			},
+			key: jwtee.NewRSAPublicKey(&private.PublicKey),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidSignature, err)
+			},
+		},
+		{
+			desc:   "failed verifying signature with salt length not equal to hash size",
+			signer: signer.NewPS256(),
+			sign: func(s *signer.RSAPSS) ([]byte, error) {
+				return zeroSalted, nil
+			},
+			key: jwtee.NewRSAPublicKey(&private.PublicKey),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidSignature, err)
+			},
+		},
+		{
+			desc:   "failed verifying with shared secret",
+			signer: signer.NewPS256(),
+			sign: func(s *signer.RSAPSS) ([]byte, error) {
+				return s.Sign(payload, jwtee.NewRSAPrivateKey(private))
+			},
+			key: jwtee.NewSharedSecretKey([]byte(`1234`)),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidKey, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			signature, err := test.sign(test.signer)
+			assert.NoError(t, err)
+
+			err = test.signer.Verify(signature, payload, test.key)
+			test.checker(t, err)
+		})
+	}
+}
+
+func TestRSAPSS_BuildAndVerify(t *testing.T) {
+	private := generateRSAKey(t)
+	pssSigner := signer.NewPS512()
+
+	parts, err := jwtee.NewTokenBuilder().Build(testclaims{Name: "John Doe"}, pssSigner, jwtee.NewRSAPrivateKey(private))
+	assert.NoError(t, err)
+
+	raw, err := parts.MarshalText()
+	assert.NoError(t, err)
+
+	verifier := jwtee.NewPartsVerifier(pssSigner, jwtee.NewRSAPublicKey(&private.PublicKey))
+	parsed, err := jwtee.NewVerifyingParser(jwtee.NewJSONParser(), verifier).Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, jwtee.Header{Typ: "JWT", Alg: jwtee.PS512}, parsed.Header())
+}
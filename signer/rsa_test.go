@@ -0,0 +1,129 @@
+package signer_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	return private
+}
+
+func TestRSA_SignVerify(t *testing.T) {
+	private := generateRSAKey(t)
+	other := generateRSAKey(t)
+	payload := []byte(`eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRG9lIiwiaWF0IjoxNTE2MjM5MDIyfQ`)
+
+	tests := []struct {
+		desc      string
+		signer    *signer.RSA
+		signKey   jwtee.Key
+		verifyKey jwtee.Key
+		payload   []byte
+		checker   func(t *testing.T, err error)
+	}{
+		{
+			desc:      "successful verifying RS256",
+			signer:    signer.NewRS256(),
+			signKey:   jwtee.NewRSAPrivateKey(private),
+			verifyKey: jwtee.NewRSAPublicKey(&private.PublicKey),
+			payload:   payload,
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:      "successful verifying RS384",
+			signer:    signer.NewRS384(),
+			signKey:   jwtee.NewRSAPrivateKey(private),
+			verifyKey: jwtee.NewRSAPublicKey(&private.PublicKey),
+			payload:   payload,
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:      "successful verifying RS512 with private key",
+			signer:    signer.NewRS512(),
+			signKey:   jwtee.NewRSAPrivateKey(private),
+			verifyKey: jwtee.NewRSAPrivateKey(private),
+			payload:   payload,
+			checker: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			desc:      "failed verifying with another public key",
+			signer:    signer.NewRS256(),
+			signKey:   jwtee.NewRSAPrivateKey(private),
+			verifyKey: jwtee.NewRSAPublicKey(&other.PublicKey),
+			payload:   payload,
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidSignature, err)
+			},
+		},
+		{
+			desc:      "failed verifying modified payload",
+			signer:    signer.NewRS256(),
+			signKey:   jwtee.NewRSAPrivateKey(private),
+			verifyKey: jwtee.NewRSAPublicKey(&private.PublicKey),
+			payload:   append(append([]byte{}, payload...), 'x'),
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidSignature, err)
+			},
+		},
+		{
+			desc:      "failed verifying with shared secret",
+			signer:    signer.NewRS256(),
+			signKey:   jwtee.NewRSAPrivateKey(private),
+			verifyKey: jwtee.NewSharedSecretKey([]byte(`1234`)),
+			payload:   payload,
+			checker: func(t *testing.T, err error) {
+				assert.Equal(t, jwtee.ErrInvalidKey, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			signature, err := test.signer.Sign(payload, test.signKey)
+			assert.NoError(t, err)
+
+			err = test.signer.Verify(signature, test.payload, test.verifyKey)
+			test.checker(t, err)
+		})
+	}
+}
+
+func TestRSA_SignWithPublicKey(t *testing.T) {
+	private := generateRSAKey(t)
+
+	_, err := signer.NewRS256().Sign([]byte(`payload`), jwtee.NewRSAPublicKey(&private.PublicKey))
+	assert.Equal(t, jwtee.ErrInvalidKey, err)
+}
+
+func TestRSA_BuildAndVerify(t *testing.T) {
+	private := generateRSAKey(t)
+	rsaSigner := signer.NewRS256()
+
+	parts, err := jwtee.NewTokenBuilder().Build(testclaims{Name: "John Doe"}, rsaSigner, jwtee.NewRSAPrivateKey(private))
+	assert.NoError(t, err)
+
+	raw, err := parts.MarshalText()
+	assert.NoError(t, err)
+
+	verifier := jwtee.NewPartsVerifier(rsaSigner, jwtee.NewRSAPublicKey(&private.PublicKey))
+	parsed, err := jwtee.NewVerifyingParser(jwtee.NewJSONParser(), verifier).Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, jwtee.RS256, parsed.Header().Alg)
+}
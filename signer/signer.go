@@ -0,0 +1,47 @@
+package signer
+
+import (
+	"errors"
+
+	"github.com/furdarius/jwtee"
+)
+
+// Block represents signer lookup errors.
+var (
+	ErrUnsupportedAlgorithm = errors.New("algorithm is not supported")
+)
+
+// ForAlgorithm returns Signer implementing given algorithm.
+// Unsecured "none" algorithm is never returned, use NewNone explicitly.
+func ForAlgorithm(alg jwtee.Algorithm) (jwtee.Signer, error) {
+	switch alg {
+	case jwtee.HS256:
+		return NewHS256(), nil
+	case jwtee.HS384:
+		return NewHS384(), nil
+	case jwtee.HS512:
+		return NewHS512(), nil
+	case jwtee.RS256:
+		return NewRS256(), nil
+	case jwtee.RS384:
+		return NewRS384(), nil
+	case jwtee.RS512:
+		return NewRS512(), nil
+	case jwtee.ES256:
+		return NewES256(), nil
+	case jwtee.ES384:
+		return NewES384(), nil
+	case jwtee.ES512:
+		return NewES512(), nil
+	case jwtee.PS256:
+		return NewPS256(), nil
+	case jwtee.PS384:
+		return NewPS384(), nil
+	case jwtee.PS512:
+		return NewPS512(), nil
+	case jwtee.EdDSA:
+		return NewEdDSA(), nil
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
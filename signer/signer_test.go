@@ -0,0 +1,18 @@
+package signer_test
+
+import (
+	"encoding/json"
+
+	"github.com/furdarius/jwtee"
+)
+
+type testclaims struct {
+	jwtee.RegisteredClaims
+
+	Name string `json:"name"`
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c testclaims) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(c)
+}
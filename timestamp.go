@@ -1,26 +1,56 @@
 package jwtee
 
 import (
+	"math"
 	"strconv"
 	"time"
 )
 
 // Timestamp represents time as number of seconds from 1970-01-01T00:00:00Z UTC until the specified moment.
-type Timestamp int64
+// It may contain fractional seconds.
+// @see https://tools.ietf.org/html/rfc7519#section-2
+type Timestamp float64
 
-// Time used to convert int64 to time.Time.
+// NewTimestamp returns Timestamp of t truncated to whole seconds,
+// since many verifiers accept only integer NumericDate.
+// Zero time results in zero Timestamp.
+func NewTimestamp(t time.Time) Timestamp {
+	if t.IsZero() {
+		return 0
+	}
+
+	return Timestamp(t.Unix())
+}
+
+// NewPreciseTimestamp returns Timestamp of t, keeping sub-second precision.
+// Zero time results in zero Timestamp.
+func NewPreciseTimestamp(t time.Time) Timestamp {
+	if t.IsZero() {
+		return 0
+	}
+
+	return Timestamp(float64(t.Unix()) + float64(t.Nanosecond())/float64(time.Second))
+}
+
+// TimestampFromNow returns Timestamp of the moment d after current time, truncated to whole seconds.
+// Negative d points to the past.
+func TimestampFromNow(d time.Duration) Timestamp {
+	return NewTimestamp(time.Now().Add(d))
+}
+
+// Time used to convert Timestamp to time.Time.
 func (t Timestamp) Time() time.Time {
 	if t == 0 {
 		return time.Time{}
 	}
 
-	return time.Unix(int64(t), 0)
+	sec, frac := math.Modf(float64(t))
+
+	return time.Unix(int64(sec), int64(math.Round(frac*float64(time.Second))))
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
-// It supports number and null input.
-// 0 will not be considered a null Float.
-// It also supports unmarshalling a sql.NullFloat64.
+// It supports integer, fractional and exponent number and null input.
 func (t *Timestamp) UnmarshalJSON(data []byte) error {
 	s := string(data)
 
@@ -29,12 +59,23 @@ func (t *Timestamp) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	q, err := strconv.ParseInt(s, 10, 64)
+	q, err := strconv.ParseFloat(s, 64)
 	if err != nil {
 		return err
 	}
 
-	*(*int64)(t) = q
+	// Seconds out of int64 range would wrap around on conversion to time.Time.
+	if math.IsInf(q, 0) || math.IsNaN(q) || q >= math.MaxInt64 || q < math.MinInt64 {
+		return strconv.ErrRange
+	}
+
+	*t = Timestamp(q)
 
 	return nil
 }
+
+// MarshalJSON implements json.Marshaler.
+// Integer Timestamp is marshaled without fractional part.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return strconv.AppendFloat(nil, float64(t), 'f', -1, 64), nil
+}
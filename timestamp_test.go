@@ -0,0 +1,144 @@
+package jwtee_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/furdarius/jwtee"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		desc    string
+		data    string
+		checker func(t *testing.T, ts jwtee.Timestamp, err error)
+	}{
+		{
+			desc: "successful unmarshalling of integer",
+			data: `1516239022`,
+			checker: func(t *testing.T, ts jwtee.Timestamp, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, time.Unix(1516239022, 0), ts.Time())
+			},
+		},
+		{
+			desc: "successful unmarshalling of fractional seconds",
+			data: `1516239022.5`,
+			checker: func(t *testing.T, ts jwtee.Timestamp, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, time.Unix(1516239022, 500000000), ts.Time())
+			},
+		},
+		{
+			desc: "successful unmarshalling of exponent notation",
+			data: `1.516239022e9`,
+			checker: func(t *testing.T, ts jwtee.Timestamp, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, time.Unix(1516239022, 0), ts.Time())
+			},
+		},
+		{
+			desc: "successful unmarshalling of null",
+			data: `null`,
+			checker: func(t *testing.T, ts jwtee.Timestamp, err error) {
+				assert.NoError(t, err)
+				assert.True(t, ts.Time().IsZero())
+			},
+		},
+		{
+			desc: "failed on value above int64 seconds range",
+			data: `1e300`,
+			checker: func(t *testing.T, ts jwtee.Timestamp, err error) {
+				assert.Equal(t, strconv.ErrRange, err)
+				assert.Equal(t, jwtee.Timestamp(0), ts)
+			},
+		},
+		{
+			desc: "failed on value below int64 seconds range",
+			data: `-1e19`,
+			checker: func(t *testing.T, ts jwtee.Timestamp, err error) {
+				assert.Equal(t, strconv.ErrRange, err)
+				assert.Equal(t, jwtee.Timestamp(0), ts)
+			},
+		},
+		{
+			desc: "failed on string",
+			data: `"1516239022"`,
+			checker: func(t *testing.T, ts jwtee.Timestamp, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			var ts jwtee.Timestamp
+
+			err := json.Unmarshal([]byte(tt.data), &ts)
+
+			tt.checker(t, ts, err)
+		})
+	}
+}
+
+func TestTimestamp_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		desc     string
+		ts       jwtee.Timestamp
+		expected string
+	}{
+		{
+			desc:     "integer timestamp",
+			ts:       1516239022,
+			expected: `1516239022`,
+		},
+		{
+			desc:     "fractional timestamp",
+			ts:       1516239022.25,
+			expected: `1516239022.25`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			data, err := json.Marshal(tt.ts)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(data))
+
+			var decoded jwtee.Timestamp
+			assert.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tt.ts, decoded)
+		})
+	}
+}
+
+func TestNewTimestamp(t *testing.T) {
+	moment := time.Unix(1516239022, 250000000)
+
+	assert.Equal(t, jwtee.Timestamp(1516239022), jwtee.NewTimestamp(moment))
+	assert.Equal(t, jwtee.Timestamp(0), jwtee.NewTimestamp(time.Time{}))
+
+	data, err := json.Marshal(jwtee.NewTimestamp(moment))
+	assert.NoError(t, err)
+	assert.Equal(t, `1516239022`, string(data))
+
+	ts := jwtee.TimestampFromNow(time.Hour)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), ts.Time(), time.Second)
+	assert.Equal(t, jwtee.Timestamp(ts.Time().Unix()), ts)
+}
+
+func TestNewPreciseTimestamp(t *testing.T) {
+	moment := time.Unix(1516239022, 250000000)
+
+	assert.Equal(t, jwtee.Timestamp(1516239022.25), jwtee.NewPreciseTimestamp(moment))
+	assert.True(t, jwtee.NewPreciseTimestamp(moment).Time().Equal(moment))
+	assert.Equal(t, jwtee.Timestamp(0), jwtee.NewPreciseTimestamp(time.Time{}))
+}
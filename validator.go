@@ -1,10 +1,29 @@
 package jwtee
 
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrClaimSetRequired indicates that constraint must be validated with ValidateClaimSet.
+	ErrClaimSetRequired = errors.New("constraint requires full claim set")
+)
+
 // Constraint used to validate JWT Claims with Constraint.
 type Constraint interface {
 	Validate(claims RegisteredClaims) error
 }
 
+// ClaimSetConstraint used to validate JWT Claims, including private ones.
+// ClaimsValidator.ValidateClaimSet prefers it to Constraint.Validate.
+type ClaimSetConstraint interface {
+	Constraint
+
+	ValidateClaimSet(claims RegisteredClaims, set ClaimSet) error
+}
+
 // Validator used to validate JWT Claims.
 type Validator interface {
 	Validate(claims RegisteredClaims, constraints ...Constraint) []error
@@ -19,12 +38,60 @@ func NewClaimsValidator() *ClaimsValidator {
 }
 
 // Validate inherited from Validator.
+// Every error is *TokenError of KindClaimsInvalid wrapping the constraint error.
 func (v *ClaimsValidator) Validate(claims RegisteredClaims, constraints ...Constraint) (errs []error) {
 	for _, constraint := range constraints {
 		err := constraint.Validate(claims)
 
 		if err != nil {
-			errs = append(errs, err)
+			errs = append(errs, newTokenError(KindClaimsInvalid, SegmentClaims, err))
+		}
+	}
+
+	return errs
+}
+
+// ValidateClaimSet decodes raw claims and validates them with Constraints.
+// ClaimSetConstraints receive full claim set, others receive RegisteredClaims only.
+func (v *ClaimsValidator) ValidateClaimSet(raw json.RawMessage, constraints ...Constraint) []error {
+	var claims RegisteredClaims
+
+	err := json.Unmarshal(raw, &claims)
+	if err != nil {
+		return []error{newTokenError(KindMalformed, SegmentClaims, err)}
+	}
+
+	return v.validate(claims, raw, constraints)
+}
+
+// validate checks claims with Constraints.
+// Claim set is decoded from raw only if one of constraints is ClaimSetConstraint.
+func (v *ClaimsValidator) validate(claims RegisteredClaims, raw json.RawMessage, constraints []Constraint) (errs []error) {
+	var set ClaimSet
+
+	for _, constraint := range constraints {
+		c, ok := constraint.(ClaimSetConstraint)
+		if !ok {
+			err := constraint.Validate(claims)
+			if err != nil {
+				errs = append(errs, newTokenError(KindClaimsInvalid, SegmentClaims, err))
+			}
+
+			continue
+		}
+
+		if set == nil {
+			var err error
+
+			set, err = ParseClaimSet(raw)
+			if err != nil {
+				return append(errs, newTokenError(KindMalformed, SegmentClaims, err))
+			}
+		}
+
+		err := c.ValidateClaimSet(claims, set)
+		if err != nil {
+			errs = append(errs, newTokenError(KindClaimsInvalid, SegmentClaims, err))
 		}
 	}
 
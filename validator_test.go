@@ -0,0 +1,89 @@
+package jwtee_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/furdarius/jwtee"
+	"github.com/furdarius/jwtee/constraint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimsValidator_ValidateClaimSet(t *testing.T) {
+	raw := json.RawMessage(`{"iss":"auth","tenant":"acme","role":"admin","groups":["dev","ops"],"scope":"read write"}`)
+
+	tests := []struct {
+		desc        string
+		claims      json.RawMessage
+		constraints []jwtee.Constraint
+		checker     func(t *testing.T, errs []error)
+	}{
+		{
+			desc:   "successful validation of private and registered claims",
+			claims: raw,
+			constraints: []jwtee.Constraint{
+				constraint.NewIssuedBy([]string{"auth"}),
+				constraint.NewClaimEquals("tenant", "acme"),
+				constraint.NewClaimOneOf("role", "user", "admin"),
+				constraint.NewClaimContains("groups", "ops"),
+				constraint.NewHasScope("write", "read"),
+			},
+			checker: func(t *testing.T, errs []error) {
+				assert.Empty(t, errs)
+			},
+		},
+		{
+			desc:   "failed validation of private claims",
+			claims: raw,
+			constraints: []jwtee.Constraint{
+				constraint.NewClaimEquals("tenant", "other"),
+				constraint.NewClaimOneOf("role", "user"),
+				constraint.NewClaimContains("groups", "qa"),
+				constraint.NewHasScope("delete"),
+				constraint.NewClaimEquals("missing", "value"),
+			},
+			checker: func(t *testing.T, errs []error) {
+				expected := []error{
+					constraint.ErrClaimMismatch,
+					constraint.ErrClaimNotAllowed,
+					constraint.ErrClaimNotContain,
+					constraint.ErrTokenInsufficientScope,
+					jwtee.ErrClaimMissed,
+				}
+
+				assert.Len(t, errs, len(expected))
+				for i, err := range errs {
+					assert.True(t, errors.Is(err, jwtee.ErrKindClaimsInvalid))
+					assert.True(t, errors.Is(err, expected[i]))
+				}
+			},
+		},
+		{
+			desc:        "failed on malformed claims",
+			claims:      json.RawMessage(`{"tenant":`),
+			constraints: []jwtee.Constraint{constraint.NewClaimEquals("tenant", "acme")},
+			checker: func(t *testing.T, errs []error) {
+				assert.Len(t, errs, 1)
+				assert.True(t, errors.Is(errs[0], jwtee.ErrKindMalformed))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			errs := jwtee.NewClaimsValidator().ValidateClaimSet(tt.claims, tt.constraints...)
+
+			tt.checker(t, errs)
+		})
+	}
+}
+
+func TestClaimsValidator_Validate_ClaimSetConstraint(t *testing.T) {
+	errs := jwtee.NewClaimsValidator().Validate(jwtee.RegisteredClaims{}, constraint.NewHasScope("read"))
+
+	assert.Len(t, errs, 1)
+	assert.True(t, errors.Is(errs[0], jwtee.ErrClaimSetRequired))
+}